@@ -0,0 +1,13 @@
+// Package bench is a table-driven comparison of bdcache against the cache
+// libraries also exercised by the standalone binaries in benchmarks/cmd:
+// run `go test -bench=. -workload=zipf` here instead of shelling out to each
+// one individually.
+package bench
+
+// Backend is the minimal surface every compared cache must implement so
+// Set/Get/Len can be driven generically from the benchmark table.
+type Backend interface {
+	Set(k string, v []byte)
+	Get(k string) ([]byte, bool)
+	Len() int
+}
@@ -0,0 +1,158 @@
+package bench
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/codeGROOVE-dev/bdcache"
+	"github.com/coocood/freecache"
+	"github.com/dgraph-io/ristretto"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/maypok86/otter/v2"
+	"github.com/vmihailenco/go-tinylfu"
+)
+
+// registry lists every backend available to the table-driven benchmarks,
+// mirroring the standalone binaries under benchmarks/cmd. Those binaries
+// remain useful for out-of-process memory isolation; this table is for
+// driving throughput/hit-rate comparisons in a single `go test -bench`.
+var registry = map[string]func(capacity int) Backend{
+	"bdcache":   newBdcacheBackend,
+	"otter":     newOtterBackend,
+	"ristretto": newRistrettoBackend,
+	"lru":       newLRUBackend,
+	"tinylfu":   newTinyLFUBackend,
+	"freecache": newFreecacheBackend,
+	"bigcache":  newBigcacheBackend,
+}
+
+type bdcacheBackend struct {
+	c *bdcache.MemoryCache[string, []byte]
+}
+
+func newBdcacheBackend(capacity int) Backend {
+	return &bdcacheBackend{c: bdcache.Memory[string, []byte](bdcache.WithSize(capacity))}
+}
+
+func (b *bdcacheBackend) Set(k string, v []byte)      { b.c.Set(k, v) }
+func (b *bdcacheBackend) Get(k string) ([]byte, bool) { return b.c.Get(k) }
+func (b *bdcacheBackend) Len() int                    { return b.c.Len() }
+
+type otterBackend struct {
+	c *otter.Cache[string, []byte]
+}
+
+func newOtterBackend(capacity int) Backend {
+	return &otterBackend{c: otter.Must(&otter.Options[string, []byte]{MaximumSize: capacity})}
+}
+
+func (b *otterBackend) Set(k string, v []byte)      { b.c.Set(k, v) }
+func (b *otterBackend) Get(k string) ([]byte, bool) { return b.c.GetIfPresent(k) }
+func (b *otterBackend) Len() int                    { return b.c.EstimatedSize() }
+
+type ristrettoBackend struct {
+	c *ristretto.Cache
+}
+
+func newRistrettoBackend(capacity int) Backend {
+	c, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: int64(capacity * 10),
+		MaxCost:     int64(capacity),
+		BufferItems: 64,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return &ristrettoBackend{c: c}
+}
+
+func (b *ristrettoBackend) Set(k string, v []byte) { b.c.Set(k, v, 1); b.c.Wait() }
+func (b *ristrettoBackend) Get(k string) ([]byte, bool) {
+	v, ok := b.c.Get(k)
+	if !ok {
+		return nil, false
+	}
+	return v.([]byte), true
+}
+func (b *ristrettoBackend) Len() int { return int(b.c.Metrics.KeysAdded()) }
+
+type lruBackend struct {
+	c *lru.Cache[string, []byte]
+}
+
+func newLRUBackend(capacity int) Backend {
+	c, err := lru.New[string, []byte](capacity)
+	if err != nil {
+		panic(err)
+	}
+	return &lruBackend{c: c}
+}
+
+func (b *lruBackend) Set(k string, v []byte)      { b.c.Add(k, v) }
+func (b *lruBackend) Get(k string) ([]byte, bool) { return b.c.Get(k) }
+func (b *lruBackend) Len() int                    { return b.c.Len() }
+
+type tinyLFUBackend struct {
+	c interface {
+		Set(item *tinylfu.Item)
+		Get(key string) (any, bool)
+	}
+}
+
+func newTinyLFUBackend(capacity int) Backend {
+	return &tinyLFUBackend{c: tinylfu.NewSync(capacity, capacity*10)}
+}
+
+func (b *tinyLFUBackend) Set(k string, v []byte) { b.c.Set(&tinylfu.Item{Key: k, Value: v}) }
+func (b *tinyLFUBackend) Get(k string) ([]byte, bool) {
+	v, ok := b.c.Get(k)
+	if !ok {
+		return nil, false
+	}
+	return v.([]byte), true
+}
+func (b *tinyLFUBackend) Len() int { return 0 } // go-tinylfu doesn't expose a size counter
+
+type freecacheBackend struct {
+	c *freecache.Cache
+}
+
+func newFreecacheBackend(capacity int) Backend {
+	return &freecacheBackend{c: freecache.NewCache(capacity * 1024)}
+}
+
+func (b *freecacheBackend) Set(k string, v []byte) { _ = b.c.Set([]byte(k), v, 0) }
+func (b *freecacheBackend) Get(k string) ([]byte, bool) {
+	v, err := b.c.Get([]byte(k))
+	return v, err == nil
+}
+func (b *freecacheBackend) Len() int { return int(b.c.EntryCount()) }
+
+type bigcacheBackend struct {
+	c *bigcache.BigCache
+}
+
+func newBigcacheBackend(capacity int) Backend {
+	config := bigcache.DefaultConfig(0)
+	config.Shards = 256
+	config.MaxEntriesInWindow = capacity
+	c, err := bigcache.New(context.Background(), config)
+	if err != nil {
+		panic(err)
+	}
+	return &bigcacheBackend{c: c}
+}
+
+func (b *bigcacheBackend) Set(k string, v []byte) { _ = b.c.Set(k, v) }
+func (b *bigcacheBackend) Get(k string) ([]byte, bool) {
+	v, err := b.c.Get(k)
+	return v, err == nil
+}
+func (b *bigcacheBackend) Len() int { return b.c.Len() }
+
+// keyFor formats the integer workload key the same way every harness in
+// benchmarks/cmd does, so hit rates are comparable across both entry points.
+func keyFor(i int) string {
+	return "key-" + strconv.Itoa(i)
+}
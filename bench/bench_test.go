@@ -0,0 +1,46 @@
+package bench
+
+import (
+	"flag"
+	"strconv"
+	"testing"
+
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/workload"
+)
+
+// Registered once for the whole package so `go test -bench=. -workload=zipf`
+// works the same way it does against the benchmarks/cmd/mem_* binaries.
+var (
+	workloadMode = flag.String("workload", "", "uniform|zipf|scan; empty runs the uniform workload")
+	workloadIter = flag.Int("iter", 200000, "operations to run per backend")
+	zipfS        = flag.Float64("zipfS", 1.1, "zipf distribution s parameter")
+	zipfV        = flag.Float64("zipfV", 1.0, "zipf distribution v parameter")
+	benchCap     = flag.Int("cap", 25000, "backend capacity")
+)
+
+// BenchmarkBackends runs every registered cache through the same fill +
+// workload sequence, so `go test -bench=. -workload=zipf` prints a
+// side-by-side comparison without shelling out to the standalone binaries.
+func BenchmarkBackends(b *testing.B) {
+	for name, newBackend := range registry {
+		b.Run(name, func(b *testing.B) {
+			backend := newBackend(*benchCap)
+			for i := range *benchCap {
+				backend.Set(keyFor(i), []byte(strconv.Itoa(i)))
+			}
+
+			b.ResetTimer()
+			mode := *workloadMode
+			if mode == "" {
+				mode = "uniform"
+			}
+			for n := 0; n < b.N; n++ {
+				result := workload.Run(mode, *workloadIter, *benchCap, *benchCap, *zipfS, *zipfV,
+					func(key string) bool { _, ok := backend.Get(key); return ok },
+					func(key string) { backend.Set(key, []byte(key)) })
+				b.ReportMetric(result.HitRatio, "hit%")
+				b.ReportMetric(float64(result.P99Ns), "p99ns")
+			}
+		})
+	}
+}
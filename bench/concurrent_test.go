@@ -0,0 +1,97 @@
+package bench
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/workload"
+)
+
+// concurrencyLevels are swept so a plot script can visualize how each
+// backend's throughput scales (or doesn't) with goroutine count.
+var concurrencyLevels = []int{1, 4, 16, 64}
+
+// TestConcurrentScaling runs each backend at every goroutine count in
+// concurrencyLevels, each goroutine doing a mixed Get/Set workload, and prints one JSON line per (backend, conc) pair
+// with aggregated ops/sec and p50/p99 latency. Single-goroutine harnesses
+// can't see lock contention; this exposes it directly.
+func TestConcurrentScaling(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping concurrency sweep in short mode")
+	}
+
+	for name, newBackend := range registry {
+		for _, conc := range concurrencyLevels {
+			backend := newBackend(*benchCap)
+			for i := range *benchCap {
+				backend.Set(keyFor(i), []byte(keyFor(i)))
+			}
+
+			result := runConcurrent(backend, conc, *workloadIter)
+			fmt.Printf(`{"backend":%q,"conc":%d,"ops_per_sec":%.0f,"p50_ns":%d,"p99_ns":%d}`+"\n",
+				name, conc, result.opsPerSec, result.p50Ns, result.p99Ns)
+		}
+	}
+}
+
+type concurrentScalingResult struct {
+	opsPerSec float64
+	p50Ns     int64
+	p99Ns     int64
+}
+
+// runConcurrent splits iter ops across conc goroutines, each doing a 75%
+// read / 25% write mix, and merges their latency histograms before
+// reporting percentiles.
+func runConcurrent(backend Backend, conc, iter int) concurrentScalingResult {
+	perGoroutine := iter / conc
+	if perGoroutine < 1 {
+		perGoroutine = 1
+	}
+
+	hists := make([]workload.Histogram, conc)
+	var wg sync.WaitGroup
+	var ops atomic.Int64
+
+	start := time.Now()
+	for g := range conc {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			hist := &hists[g]
+			for i := range perGoroutine {
+				key := keyFor(i % *benchCap)
+				opStart := time.Now()
+				if i%4 == 3 {
+					backend.Set(key, []byte(key))
+				} else {
+					backend.Get(key)
+				}
+				hist.Record(time.Since(opStart).Nanoseconds())
+			}
+			ops.Add(int64(perGoroutine))
+		}(g)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	merged := mergeHistograms(hists)
+	return concurrentScalingResult{
+		opsPerSec: float64(ops.Load()) / elapsed.Seconds(),
+		p50Ns:     merged.Quantile(0.50),
+		p99Ns:     merged.P99(),
+	}
+}
+
+// mergeHistograms combines per-goroutine bucket counts into one histogram
+// so percentiles reflect the whole run, not a single goroutine's slice of it.
+func mergeHistograms(hists []workload.Histogram) *workload.Histogram {
+	merged := &workload.Histogram{}
+	for i := range hists {
+		merged.Merge(&hists[i])
+	}
+	return merged
+}
@@ -0,0 +1,76 @@
+package bench
+
+import (
+	"github.com/cespare/xxhash/v2"
+	"github.com/codeGROOVE-dev/bdcache"
+)
+
+// ShardedCache partitions keys across N independent bdcache.Memory shards
+// by xxhash(key) % N, so that lock contention under concurrent load scales
+// down with core count instead of funneling through one mutex the way a
+// single bdcache.Memory instance does.
+type ShardedCache[K ~string, V any] struct {
+	shards []*bdcache.MemoryCache[K, V]
+}
+
+// NewShardedCache creates a ShardedCache with n shards, each sized to
+// capacity/n entries.
+func NewShardedCache[K ~string, V any](n, capacity int) *ShardedCache[K, V] {
+	if n < 1 {
+		n = 1
+	}
+	perShard := capacity / n
+	if perShard < 1 {
+		perShard = 1
+	}
+	shards := make([]*bdcache.MemoryCache[K, V], n)
+	for i := range shards {
+		shards[i] = bdcache.Memory[K, V](bdcache.WithSize(perShard))
+	}
+	return &ShardedCache[K, V]{shards: shards}
+}
+
+func (s *ShardedCache[K, V]) shardFor(key K) *bdcache.MemoryCache[K, V] {
+	h := xxhash.Sum64String(string(key))
+	return s.shards[h%uint64(len(s.shards))]
+}
+
+// Get reads a value from the shard that owns key.
+func (s *ShardedCache[K, V]) Get(key K) (V, bool) {
+	return s.shardFor(key).Get(key)
+}
+
+// Set writes a value to the shard that owns key.
+func (s *ShardedCache[K, V]) Set(key K, val V) {
+	s.shardFor(key).Set(key, val)
+}
+
+// Len sums the length of every shard.
+func (s *ShardedCache[K, V]) Len() int {
+	var n int
+	for _, shard := range s.shards {
+		n += shard.Len()
+	}
+	return n
+}
+
+// shardedBackend adapts ShardedCache[string, []byte] to the Backend
+// interface so it slots into the same comparison table as the unsharded
+// backends.
+type shardedBackend struct {
+	c *ShardedCache[string, []byte]
+}
+
+// newShardedBackend16 uses 16 shards, a reasonable default for the core
+// counts (1/4/16/64) the concurrent harness sweeps.
+func newShardedBackend16(capacity int) Backend {
+	return &shardedBackend{c: NewShardedCache[string, []byte](16, capacity)}
+}
+
+func (b *shardedBackend) Set(k string, v []byte)      { b.c.Set(k, v) }
+func (b *shardedBackend) Get(k string) ([]byte, bool) { return b.c.Get(k) }
+func (b *shardedBackend) Len() int                    { return b.c.Len() }
+
+func init() {
+	registry["bdcache-sharded"] = newShardedBackend16
+}
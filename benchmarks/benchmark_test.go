@@ -44,6 +44,9 @@ func TestBenchmarkSuite(t *testing.T) {
 	printTestHeader("TestProxyCache", "HTTP Proxy Cache Pattern")
 	runProxyCacheHitRate()
 
+	printTestHeader("TestScanResistance", "Scan Resistance")
+	runScanResistanceBenchmark()
+
 	printTestHeader("TestLatency", "Single-Threaded Latency")
 	runPerformanceBenchmark()
 
@@ -5,58 +5,64 @@ import (
 	"flag"
 	"fmt"
 	"runtime"
-	"runtime/debug"
 	"strconv"
-	"time"
+
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/benchmem"
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/workload"
 )
 
 var keepAlive any //nolint:unused // prevents compiler from optimizing away allocations in benchmarks
 
 func main() {
-	_ = flag.Int("iter", 100000, "unused in this mode unless target is 0")
+	iter := flag.Int("iter", 100000, "operations to run when -workload is set")
 	capacity := flag.Int("cap", 25000, "capacity")
 	valSize := flag.Int("valSize", 1024, "value size")
 	target := flag.Int("target", 0, "if > 0, just fill map with this many items and exit")
+	mode := flag.String("workload", "", "if set, run a uniform/zipf/scan workload after filling: uniform|zipf|scan")
+	zipfS := flag.Float64("zipfS", 1.1, "zipf distribution s parameter")
+	zipfV := flag.Float64("zipfV", 1.0, "zipf distribution v parameter")
 	flag.Parse()
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
-
 	// Use target as capacity if specified (fair comparison for partial fills)
 	mapCap := *capacity
 	if *target > 0 {
 		mapCap = *target
 	}
-	m := make(map[string][]byte, mapCap)
 
-	if *target > 0 {
-		// Just fill with N unique items
-		for i := range *target {
-			key := "key-" + strconv.Itoa(i)
-			val := make([]byte, *valSize)
-			m[key] = val
-		}
-	} else {
-		// Fallback: fill up to capacity
-		for i := range *capacity {
-			key := "key-" + strconv.Itoa(i)
-			val := make([]byte, *valSize)
-			m[key] = val
+	var m map[string][]byte
+	sample := benchmem.Measure(func() {
+		m = make(map[string][]byte, mapCap)
+
+		if *target > 0 {
+			// Just fill with N unique items
+			for i := range *target {
+				key := "key-" + strconv.Itoa(i)
+				val := make([]byte, *valSize)
+				m[key] = val
+			}
+		} else {
+			// Fallback: fill up to capacity
+			for i := range *capacity {
+				key := "key-" + strconv.Itoa(i)
+				val := make([]byte, *valSize)
+				m[key] = val
+			}
 		}
-	}
+	})
 
 	keepAlive = m
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	time.Sleep(100 * time.Millisecond)
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
-
 	var mem runtime.MemStats
 	runtime.ReadMemStats(&mem)
 
-	fmt.Printf(`{"name":"baseline", "items":%d, "bytes":%d}`, len(m), mem.Alloc)
+	var wr workload.Result
+	if *mode != "" {
+		wr = workload.Run(*mode, *iter, *capacity, *capacity, *zipfS, *zipfV,
+			func(key string) bool { _, ok := m[key]; return ok },
+			func(key string) { m[key] = make([]byte, *valSize) })
+	}
+
+	fmt.Printf(`{"name":"baseline", "items":%d, "bytes":%d, "live_bytes":%d, "heap_objects_bytes":%d, "total_alloc_delta":%d, "gc_cycles":%d, "hit_ratio":%.4f, "ops_per_sec":%.0f, "p99_ns":%d}`,
+		len(m), mem.Alloc, sample.LiveBytes, sample.HeapObjectsBytes, sample.TotalAllocDelta, sample.GCCycles,
+		wr.HitRatio, wr.OpsPerSec, wr.P99Ns)
 }
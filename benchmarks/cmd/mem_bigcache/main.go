@@ -0,0 +1,69 @@
+// Package main benchmarks bigcache memory usage.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/benchmem"
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/workload"
+)
+
+var keepAlive any //nolint:unused // prevents compiler from optimizing away allocations in benchmarks
+
+func main() {
+	iter := flag.Int("iter", 100000, "operations to run when -workload is set")
+	capacity := flag.Int("cap", 25000, "capacity")
+	valSize := flag.Int("valSize", 1024, "value size")
+	mode := flag.String("workload", "", "if set, run a uniform/zipf/scan workload after filling: uniform|zipf|scan")
+	zipfS := flag.Float64("zipfS", 1.1, "zipf distribution s parameter")
+	zipfV := flag.Float64("zipfV", 1.0, "zipf distribution v parameter")
+	flag.Parse()
+
+	var cache *bigcache.BigCache
+	sample := benchmem.Measure(func() {
+		config := bigcache.DefaultConfig(10 * time.Minute)
+		config.Shards = 256
+		config.MaxEntriesInWindow = *capacity
+		config.MaxEntrySize = *valSize
+
+		var err error
+		cache, err = bigcache.New(context.Background(), config)
+		if err != nil {
+			panic(fmt.Sprintf("bigcache.New failed: %v", err))
+		}
+
+		// Run 3 passes for parity with the other admission-policy harnesses,
+		// even though bigcache's sharded ring buffer has no admission policy.
+		for range 3 {
+			for i := range *capacity {
+				key := "key-" + strconv.Itoa(i)
+				val := make([]byte, *valSize)
+				if err := cache.Set(key, val); err != nil {
+					panic(fmt.Sprintf("bigcache.Set failed: %v", err))
+				}
+			}
+		}
+	})
+
+	keepAlive = cache
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var wr workload.Result
+	if *mode != "" {
+		wr = workload.Run(*mode, *iter, *capacity, *capacity, *zipfS, *zipfV,
+			func(key string) bool { _, err := cache.Get(key); return err == nil },
+			func(key string) { cache.Set(key, make([]byte, *valSize)) }) //nolint:errcheck // best-effort during workload replay
+	}
+
+	fmt.Printf(`{"name":"bigcache", "items":%d, "bytes":%d, "live_bytes":%d, "heap_objects_bytes":%d, "total_alloc_delta":%d, "gc_cycles":%d, "hit_ratio":%.4f, "ops_per_sec":%.0f, "p99_ns":%d}`,
+		cache.Len(), mem.Alloc, sample.LiveBytes, sample.HeapObjectsBytes, sample.TotalAllocDelta, sample.GCCycles,
+		wr.HitRatio, wr.OpsPerSec, wr.P99Ns)
+}
@@ -5,52 +5,57 @@ import (
 	"flag"
 	"fmt"
 	"runtime"
-	"runtime/debug"
 	"strconv"
-	"time"
 
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/benchmem"
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/workload"
 	"github.com/coocood/freecache"
 )
 
 var keepAlive any //nolint:unused // prevents compiler from optimizing away allocations in benchmarks
 
 func main() {
-	_ = flag.Int("iter", 100000, "unused in this mode")
+	iter := flag.Int("iter", 100000, "operations to run when -workload is set")
 	capacity := flag.Int("cap", 25000, "capacity")
 	valSize := flag.Int("valSize", 1024, "value size")
+	mode := flag.String("workload", "", "if set, run a uniform/zipf/scan workload after filling: uniform|zipf|scan")
+	zipfS := flag.Float64("zipfS", 1.1, "zipf distribution s parameter")
+	zipfV := flag.Float64("zipfV", 1.0, "zipf distribution v parameter")
 	flag.Parse()
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
-
 	// Freecache size in bytes
 	overhead := 256 // per entry overhead estimate
 	size := *capacity * (*valSize + overhead)
-	cache := freecache.NewCache(size)
-
-	// Run 3 passes to ensure admission policies accept the items
-	for range 3 {
-		for i := range *capacity {
-			key := "key-" + strconv.Itoa(i)
-			val := make([]byte, *valSize)
-			if err := cache.Set([]byte(key), val, 0); err != nil {
-				panic(fmt.Sprintf("freecache.Set failed: %v", err))
+
+	var cache *freecache.Cache
+	sample := benchmem.Measure(func() {
+		cache = freecache.NewCache(size)
+
+		// Run 3 passes to ensure admission policies accept the items
+		for range 3 {
+			for i := range *capacity {
+				key := "key-" + strconv.Itoa(i)
+				val := make([]byte, *valSize)
+				if err := cache.Set([]byte(key), val, 0); err != nil {
+					panic(fmt.Sprintf("freecache.Set failed: %v", err))
+				}
 			}
 		}
-	}
+	})
 
 	keepAlive = cache
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	time.Sleep(100 * time.Millisecond)
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
-
 	var mem runtime.MemStats
 	runtime.ReadMemStats(&mem)
 
-	fmt.Printf(`{"name":"freecache", "items":%d, "bytes":%d}`, cache.EntryCount(), mem.Alloc)
+	var wr workload.Result
+	if *mode != "" {
+		wr = workload.Run(*mode, *iter, *capacity, *capacity, *zipfS, *zipfV,
+			func(key string) bool { _, err := cache.Get([]byte(key)); return err == nil },
+			func(key string) { cache.Set([]byte(key), make([]byte, *valSize), 0) }) //nolint:errcheck // best-effort during workload replay
+	}
+
+	fmt.Printf(`{"name":"freecache", "items":%d, "bytes":%d, "live_bytes":%d, "heap_objects_bytes":%d, "total_alloc_delta":%d, "gc_cycles":%d, "hit_ratio":%.4f, "ops_per_sec":%.0f, "p99_ns":%d}`,
+		cache.EntryCount(), mem.Alloc, sample.LiveBytes, sample.HeapObjectsBytes, sample.TotalAllocDelta, sample.GCCycles,
+		wr.HitRatio, wr.OpsPerSec, wr.P99Ns)
 }
@@ -3,52 +3,53 @@ package main
 
 import (
 	"flag"
-	"fmt"
-	"runtime"
-	"runtime/debug"
-	"strconv"
-	"time"
 
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/memharness"
 	lru "github.com/hashicorp/golang-lru/v2"
 )
 
-var keepAlive any //nolint:unused // prevents compiler from optimizing away allocations in benchmarks
+// lruHarness adapts hashicorp/golang-lru to memharness.Harness.
+type lruHarness struct{}
 
-func main() {
-	_ = flag.Int("iter", 100000, "unused in this mode")
-	capacity := flag.Int("cap", 25000, "capacity")
-	valSize := flag.Int("valSize", 1024, "value size")
-	flag.Parse()
+func (lruHarness) Name() string { return "lru" }
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
-
-	cache, err := lru.New[string, []byte](*capacity)
+func (lruHarness) New(capacity int) (memharness.Cache, error) {
+	c, err := lru.New[string, []byte](capacity)
 	if err != nil {
-		panic(fmt.Sprintf("lru.New failed: %v", err))
+		return nil, err
 	}
+	return &lruCache{cache: c}, nil
+}
 
-	// Run 3 passes to ensure admission policies accept the items
-	for range 3 {
-		for i := range *capacity {
-			key := "key-" + strconv.Itoa(i)
-			val := make([]byte, *valSize)
-			cache.Add(key, val)
-		}
-	}
+type lruCache struct {
+	cache *lru.Cache[string, []byte]
+}
+
+func (c *lruCache) Set(key string, val []byte) { c.cache.Add(key, val) }
 
-	keepAlive = cache
+func (c *lruCache) Get(key string) bool {
+	_, ok := c.cache.Get(key)
+	return ok
+}
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	time.Sleep(100 * time.Millisecond)
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
+func (c *lruCache) Len() int { return c.cache.Len() }
 
-	var mem runtime.MemStats
-	runtime.ReadMemStats(&mem)
+func main() {
+	iter := flag.Int("iter", 100000, "operations to run when -workload is set")
+	capacity := flag.Int("cap", 25000, "capacity")
+	valSize := flag.Int("valSize", 1024, "value size")
+	mode := flag.String("workload", "", "if set, run a uniform/zipf/scan/churn workload after filling: uniform|zipf|scan|churn")
+	zipfS := flag.Float64("zipfS", 1.1, "zipf distribution s parameter")
+	zipfV := flag.Float64("zipfV", 1.0, "zipf distribution v parameter")
+	flag.Parse()
 
-	fmt.Printf(`{"name":"lru", "items":%d, "bytes":%d}`, cache.Len(), mem.Alloc)
+	memharness.Run(lruHarness{}, memharness.Options{
+		Capacity: *capacity,
+		ValSize:  *valSize,
+		Passes:   3, // ensure the admission policy accepts the items
+		Mode:     *mode,
+		Iter:     *iter,
+		ZipfS:    *zipfS,
+		ZipfV:    *zipfV,
+	})
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+)
+
+var errOffHeapAlloc = errors.New("off-heap allocation failed")
+
+// Value is a reference-counted byte buffer backed by memory outside the Go
+// heap, so its bytes never contribute to runtime.MemStats.Alloc or GC scan
+// time. Call Alloc to obtain one and Release when done with it; a runtime
+// finalizer frees any copy the caller forgets to Release explicitly.
+type Value struct {
+	ptr  unsafe.Pointer
+	n    int
+	refs atomic.Int32
+}
+
+// Alloc reserves n bytes of off-heap memory and returns a Value with a
+// reference count of 1.
+func Alloc(n int) (*Value, error) {
+	ptr, err := allocOffHeap(n)
+	if err != nil {
+		return nil, err
+	}
+	v := &Value{ptr: ptr, n: n}
+	v.refs.Store(1)
+	runtime.SetFinalizer(v, (*Value).finalize)
+	return v, nil
+}
+
+// Bytes returns a slice viewing the off-heap memory. The slice is only valid
+// until the last Release.
+func (v *Value) Bytes() []byte {
+	if v.n == 0 {
+		return nil
+	}
+	return unsafe.Slice((*byte)(v.ptr), v.n)
+}
+
+// Retain increments the reference count, e.g. when a cache entry is cloned
+// into a second slot without copying the underlying bytes.
+func (v *Value) Retain() {
+	v.refs.Add(1)
+}
+
+// Release decrements the reference count and frees the off-heap memory once
+// it reaches zero. Callers must not touch Bytes() after their own Release.
+func (v *Value) Release() {
+	if v.refs.Add(-1) == 0 {
+		freeOffHeap(v.ptr, v.n)
+		v.ptr = nil
+		runtime.SetFinalizer(v, nil)
+	}
+}
+
+// finalize is a safety net for Values whose owner forgot to call Release,
+// so a leaked entry doesn't hold off-heap memory forever.
+func (v *Value) finalize() {
+	if v.ptr != nil && v.refs.Load() > 0 {
+		freeOffHeap(v.ptr, v.n)
+		v.ptr = nil
+	}
+}
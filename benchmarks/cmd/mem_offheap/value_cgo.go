@@ -0,0 +1,31 @@
+//go:build cgo
+
+package main
+
+/*
+#include <stdlib.h>
+#include <string.h>
+*/
+import "C"
+
+import "unsafe"
+
+// allocOffHeap reserves n bytes outside the Go heap via libc malloc.
+func allocOffHeap(n int) (unsafe.Pointer, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	ptr := C.malloc(C.size_t(n))
+	if ptr == nil {
+		return nil, errOffHeapAlloc
+	}
+	C.memset(ptr, 0, C.size_t(n))
+	return ptr, nil
+}
+
+// freeOffHeap releases memory obtained from allocOffHeap.
+func freeOffHeap(ptr unsafe.Pointer, _ int) {
+	if ptr != nil {
+		C.free(ptr)
+	}
+}
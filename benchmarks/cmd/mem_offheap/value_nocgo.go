@@ -0,0 +1,30 @@
+//go:build !cgo
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// allocOffHeap reserves n bytes outside the Go heap via an anonymous mmap.
+// This is the pure-Go fallback used when cgo is unavailable (CGO_ENABLED=0).
+func allocOffHeap(n int) (unsafe.Pointer, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	b, err := syscall.Mmap(-1, 0, n, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+	return unsafe.Pointer(&b[0]), nil
+}
+
+// freeOffHeap releases memory obtained from allocOffHeap.
+func freeOffHeap(ptr unsafe.Pointer, n int) {
+	if ptr == nil || n == 0 {
+		return
+	}
+	b := unsafe.Slice((*byte)(ptr), n)
+	_ = syscall.Munmap(b)
+}
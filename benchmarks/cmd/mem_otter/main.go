@@ -5,45 +5,40 @@ import (
 	"flag"
 	"fmt"
 	"runtime"
-	"runtime/debug"
 	"strconv"
-	"time"
 
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/benchmem"
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/workload"
 	"github.com/maypok86/otter/v2"
 )
 
 var keepAlive any //nolint:unused // prevents compiler from optimizing away allocations in benchmarks
 
 func main() {
-	_ = flag.Int("iter", 100000, "unused in this mode")
+	iter := flag.Int("iter", 100000, "operations to run when -workload is set")
 	capacity := flag.Int("cap", 25000, "capacity")
 	valSize := flag.Int("valSize", 1024, "value size")
+	mode := flag.String("workload", "", "if set, run a uniform/zipf/scan workload after filling: uniform|zipf|scan")
+	zipfS := flag.Float64("zipfS", 1.1, "zipf distribution s parameter")
+	zipfV := flag.Float64("zipfV", 1.0, "zipf distribution v parameter")
 	flag.Parse()
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
-
-	cache := otter.Must(&otter.Options[string, []byte]{MaximumSize: *capacity})
-
-	// Run 3 passes to ensure admission policies accept the items
-	for range 3 {
-		for i := range *capacity {
-			key := "key-" + strconv.Itoa(i)
-			val := make([]byte, *valSize)
-			cache.Set(key, val)
+	var cache *otter.Cache[string, []byte]
+	sample := benchmem.Measure(func() {
+		cache = otter.Must(&otter.Options[string, []byte]{MaximumSize: *capacity})
+
+		// Run 3 passes to ensure admission policies accept the items
+		for range 3 {
+			for i := range *capacity {
+				key := "key-" + strconv.Itoa(i)
+				val := make([]byte, *valSize)
+				cache.Set(key, val)
+			}
 		}
-	}
+	})
 
 	keepAlive = cache
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	time.Sleep(100 * time.Millisecond)
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
-
 	var mem runtime.MemStats
 	runtime.ReadMemStats(&mem)
 
@@ -55,5 +50,14 @@ func main() {
 		}
 	}
 
-	fmt.Printf(`{"name":"otter", "items":%d, "bytes":%d}`, count, mem.Alloc)
+	var wr workload.Result
+	if *mode != "" {
+		wr = workload.Run(*mode, *iter, *capacity, *capacity, *zipfS, *zipfV,
+			func(key string) bool { _, ok := cache.GetIfPresent(key); return ok },
+			func(key string) { cache.Set(key, make([]byte, *valSize)) })
+	}
+
+	fmt.Printf(`{"name":"otter", "items":%d, "bytes":%d, "live_bytes":%d, "heap_objects_bytes":%d, "total_alloc_delta":%d, "gc_cycles":%d, "hit_ratio":%.4f, "ops_per_sec":%.0f, "p99_ns":%d}`,
+		count, mem.Alloc, sample.LiveBytes, sample.HeapObjectsBytes, sample.TotalAllocDelta, sample.GCCycles,
+		wr.HitRatio, wr.OpsPerSec, wr.P99Ns)
 }
@@ -3,66 +3,73 @@ package main
 
 import (
 	"flag"
-	"fmt"
-	"runtime"
-	"runtime/debug"
 	"strconv"
-	"time"
 
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/memharness"
 	"github.com/dgraph-io/ristretto"
 )
 
-var keepAlive any //nolint:unused // prevents compiler from optimizing away allocations in benchmarks
+// ristrettoHarness adapts ristretto.Cache to memharness.Harness.
+type ristrettoHarness struct{}
 
-func main() {
-	_ = flag.Int("iter", 100000, "unused in this mode")
-	capacity := flag.Int("cap", 25000, "capacity")
-	valSize := flag.Int("valSize", 1024, "value size")
-	flag.Parse()
-
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
+func (ristrettoHarness) Name() string { return "ristretto" }
 
-	// Ristretto config: NumCounters should be 10x MaxCost for best performance
-	cache, err := ristretto.NewCache(&ristretto.Config{
-		NumCounters:        int64(*capacity * 10),
-		MaxCost:            int64(*capacity),
+func (ristrettoHarness) New(capacity int) (memharness.Cache, error) {
+	// Ristretto config: NumCounters should be 10x MaxCost for best performance.
+	c, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters:        int64(capacity * 10),
+		MaxCost:            int64(capacity),
 		BufferItems:        64 * 1024, // Increase buffer to avoid drops during ingestion
 		IgnoreInternalCost: true,
 	})
 	if err != nil {
-		panic(fmt.Sprintf("ristretto.NewCache failed: %v", err))
+		return nil, err
 	}
+	return &ristrettoCache{cache: c, capacity: capacity}, nil
+}
 
-	// Run 3 passes to ensure admission policies accept the items
-	for range 3 {
-		for i := range *capacity {
-			key := "key-" + strconv.Itoa(i)
-			val := make([]byte, *valSize)
-			cache.Set(key, val, 1) // Cost 1 per item
-		}
-	}
-	cache.Wait()
-
-	keepAlive = cache
+type ristrettoCache struct {
+	cache    *ristretto.Cache
+	capacity int
+}
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	time.Sleep(100 * time.Millisecond)
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
+func (c *ristrettoCache) Set(key string, val []byte) { c.cache.Set(key, val, 1) }
 
-	var mem runtime.MemStats
-	runtime.ReadMemStats(&mem)
+func (c *ristrettoCache) Get(key string) bool {
+	_, ok := c.cache.Get(key)
+	return ok
+}
 
+// Len counts surviving keys by probing the fill range directly, since
+// ristretto doesn't track admitted-item count.
+func (c *ristrettoCache) Len() int {
 	count := 0
-	for i := range *capacity {
-		if _, ok := cache.Get("key-" + strconv.Itoa(i)); ok {
+	for i := range c.capacity {
+		if _, ok := c.cache.Get("key-" + strconv.Itoa(i)); ok {
 			count++
 		}
 	}
+	return count
+}
+
+func (c *ristrettoCache) Wait() { c.cache.Wait() }
 
-	fmt.Printf(`{"name":"ristretto", "items":%d, "bytes":%d}`, count, mem.Alloc)
+func main() {
+	iter := flag.Int("iter", 100000, "operations to run when -workload is set")
+	capacity := flag.Int("cap", 25000, "capacity")
+	valSize := flag.Int("valSize", 1024, "value size")
+	mode := flag.String("workload", "", "if set, run a uniform/zipf/scan/churn workload after filling: uniform|zipf|scan|churn")
+	zipfS := flag.Float64("zipfS", 1.1, "zipf distribution s parameter")
+	zipfV := flag.Float64("zipfV", 1.0, "zipf distribution v parameter")
+	flag.Parse()
+
+	memharness.Run(ristrettoHarness{}, memharness.Options{
+		Capacity: *capacity,
+		ValSize:  *valSize,
+		Passes:   3, // ensure the admission policy accepts the items
+		Mode:     *mode,
+		Iter:     *iter,
+		ZipfS:    *zipfS,
+		ZipfV:    *zipfV,
+	})
 }
@@ -5,50 +5,48 @@ import (
 	"flag"
 	"fmt"
 	"runtime"
-	"runtime/debug"
 	"strconv"
-	"time"
 
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/benchmem"
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/workload"
 	"github.com/vmihailenco/go-tinylfu"
 )
 
 var keepAlive any //nolint:unused // prevents compiler from optimizing away allocations in benchmarks
 
 func main() {
-	_ = flag.Int("iter", 100000, "unused in this mode")
+	iter := flag.Int("iter", 100000, "operations to run when -workload is set")
 	capacity := flag.Int("cap", 25000, "capacity")
 	valSize := flag.Int("valSize", 1024, "value size")
+	mode := flag.String("workload", "", "if set, run a uniform/zipf/scan workload after filling: uniform|zipf|scan")
+	zipfS := flag.Float64("zipfS", 1.1, "zipf distribution s parameter")
+	zipfV := flag.Float64("zipfV", 1.0, "zipf distribution v parameter")
 	flag.Parse()
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
+	var cache interface {
+		Get(key string) (any, bool)
+		Set(item *tinylfu.Item)
+	}
+	sample := benchmem.Measure(func() {
+		cache = tinylfu.NewSync(*capacity, *capacity*10)
 
-	cache := tinylfu.NewSync(*capacity, *capacity*10)
+		// Set and immediately access items to force promotion from Window to Main.
+		// TinyLFU is scan-resistant and will reject a pure loop (0..cap) if the loop is larger than the Window size (~1%).
+		// By accessing immediately, we prove the item has frequency > 1.
+		for i := range *capacity {
+			key := "key-" + strconv.Itoa(i)
+			val := make([]byte, *valSize)
+			cache.Set(&tinylfu.Item{Key: key, Value: val})
 
-	// Set and immediately access items to force promotion from Window to Main.
-	// TinyLFU is scan-resistant and will reject a pure loop (0..cap) if the loop is larger than the Window size (~1%).
-	// By accessing immediately, we prove the item has frequency > 1.
-	for i := range *capacity {
-		key := "key-" + strconv.Itoa(i)
-		val := make([]byte, *valSize)
-		cache.Set(&tinylfu.Item{Key: key, Value: val})
-
-		// Boost frequency
-		cache.Get(key)
-		cache.Get(key)
-		cache.Get(key)
-	}
+			// Boost frequency
+			cache.Get(key)
+			cache.Get(key)
+			cache.Get(key)
+		}
+	})
 
 	keepAlive = cache
 
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	time.Sleep(100 * time.Millisecond)
-	//nolint:revive // explicit GC required for accurate memory benchmarking
-	runtime.GC()
-	debug.FreeOSMemory()
-
 	var mem runtime.MemStats
 	runtime.ReadMemStats(&mem)
 
@@ -60,5 +58,14 @@ func main() {
 		}
 	}
 
-	fmt.Printf(`{"name":"tinylfu", "items":%d, "bytes":%d}`, count, mem.Alloc)
+	var wr workload.Result
+	if *mode != "" {
+		wr = workload.Run(*mode, *iter, *capacity, *capacity, *zipfS, *zipfV,
+			func(key string) bool { _, ok := cache.Get(key); return ok },
+			func(key string) { cache.Set(&tinylfu.Item{Key: key, Value: make([]byte, *valSize)}) })
+	}
+
+	fmt.Printf(`{"name":"tinylfu", "items":%d, "bytes":%d, "live_bytes":%d, "heap_objects_bytes":%d, "total_alloc_delta":%d, "gc_cycles":%d, "hit_ratio":%.4f, "ops_per_sec":%.0f, "p99_ns":%d}`,
+		count, mem.Alloc, sample.LiveBytes, sample.HeapObjectsBytes, sample.TotalAllocDelta, sample.GCCycles,
+		wr.HitRatio, wr.OpsPerSec, wr.P99Ns)
 }
@@ -0,0 +1,97 @@
+// Package benchmem samples runtime/metrics counters around a memory
+// benchmark's load phase, so harnesses get live-heap and fragmentation data
+// instead of the single runtime.MemStats.Alloc figure, which conflates
+// cache overhead with transient allocations and misses fragmentation.
+package benchmem
+
+import (
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+)
+
+// Sample is the memory footprint delta observed around a measured region.
+type Sample struct {
+	LiveBytes        int64  `json:"live_bytes"`         // /gc/heap/live:bytes at the end
+	HeapObjectsBytes int64  `json:"heap_objects_bytes"` // /memory/classes/heap/objects:bytes at the end
+	HeapUnusedBytes  int64  `json:"heap_unused_bytes"`  // /memory/classes/heap/unused:bytes at the end
+	TotalAllocDelta  int64  `json:"total_alloc_delta"`  // /gc/heap/allocs:bytes, end minus start
+	GCCycles         uint64 `json:"gc_cycles"`          // number of completed GC cycles during fn
+}
+
+// metric names read from runtime/metrics; kept in one slice so Read() and
+// the indices below can't drift apart.
+var sampleNames = []string{
+	"/memory/classes/heap/objects:bytes",
+	"/memory/classes/heap/unused:bytes",
+	"/gc/heap/live:bytes",
+	"/gc/heap/allocs:bytes",
+	"/gc/cycles/total:gc-cycles",
+}
+
+const (
+	idxHeapObjects = iota
+	idxHeapUnused
+	idxHeapLive
+	idxHeapAllocs
+	idxGCCycles
+)
+
+// Measure runs fn under measurement, forcing two GCs before and after with
+// GC temporarily disabled in between so the measured region is stable, and
+// returns the resulting memory deltas.
+func Measure(fn func()) Sample {
+	settleGC()
+	before := read()
+
+	beforeAllocs := before[idxHeapAllocs]
+	beforeCycles := before[idxGCCycles]
+
+	old := debug.SetGCPercent(-1)
+	fn()
+	debug.SetGCPercent(old)
+	fnCycles := read()[idxGCCycles] - beforeCycles
+
+	settleGC()
+	after := read()
+
+	return Sample{
+		LiveBytes:        int64(after[idxHeapLive]),
+		HeapObjectsBytes: int64(after[idxHeapObjects]),
+		HeapUnusedBytes:  int64(after[idxHeapUnused]),
+		TotalAllocDelta:  int64(after[idxHeapAllocs] - beforeAllocs),
+		GCCycles:         fnCycles,
+	}
+}
+
+// settleGC forces two GC cycles back to back, bracketing the measured
+// region so background sweeping doesn't leak into the sample.
+func settleGC() {
+	//nolint:revive // explicit GC required for accurate memory benchmarking
+	runtime.GC()
+	//nolint:revive // explicit GC required for accurate memory benchmarking
+	runtime.GC()
+}
+
+// read samples sampleNames in order and returns raw uint64 values (bytes or
+// counts, per /gc/cycles/total:gc-cycles being a counter not a byte size).
+func read() [5]uint64 {
+	samples := make([]metrics.Sample, len(sampleNames))
+	for i, name := range sampleNames {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	var out [5]uint64
+	for i, s := range samples {
+		switch s.Value.Kind() {
+		case metrics.KindUint64:
+			out[i] = s.Value.Uint64()
+		case metrics.KindFloat64:
+			out[i] = uint64(s.Value.Float64())
+		default:
+			out[i] = 0
+		}
+	}
+	return out
+}
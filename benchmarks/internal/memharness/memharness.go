@@ -0,0 +1,100 @@
+// Package memharness generalizes the per-cache memory-benchmark mains under
+// benchmarks/cmd: each one used to hand-roll the same 3-pass fill, forced
+// GC, ReadMemStats, and JSON-line output around a different cache library.
+// A Harness adapts one cache implementation; Run does the rest.
+package memharness
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/benchmem"
+	"github.com/codeGROOVE-dev/bdcache/benchmarks/internal/workload"
+)
+
+// Cache is the minimal surface Run needs from a constructed cache instance.
+type Cache interface {
+	Set(key string, val []byte)
+	Get(key string) bool
+	Len() int
+}
+
+// Waiter is implemented by caches (ristretto's buffered Set) whose writes
+// are asynchronous; Run calls Wait after the fill phase if present.
+type Waiter interface {
+	Wait()
+}
+
+// Filler lets a harness customize the fill loop for caches that need more
+// than a plain N-pass Set loop to reach steady state (e.g. tinylfu, which
+// needs an immediate read-back per item to promote it out of the Window
+// segment). Harnesses that don't implement Filler get Run's generic loop.
+type Filler interface {
+	Fill(cache Cache, capacity, valSize int)
+}
+
+// Harness adapts one cache library to Run's fill-and-measure driver.
+type Harness interface {
+	Name() string
+	New(capacity int) (Cache, error)
+}
+
+// Options configures a Run invocation; it mirrors the flags every mem_*
+// main already parsed individually.
+type Options struct {
+	Capacity int
+	ValSize  int
+	Passes   int // ignored if the harness implements Filler
+	Mode     string
+	Iter     int
+	ZipfS    float64
+	ZipfV    float64
+}
+
+// Run fills a cache built by h.New, measures its footprint, optionally
+// replays a workload against it, and prints the mem_*-style JSON line this
+// package's callers already emit.
+func Run(h Harness, opts Options) {
+	if opts.Passes < 1 {
+		opts.Passes = 1
+	}
+
+	var cache Cache
+	sample := benchmem.Measure(func() {
+		var err error
+		cache, err = h.New(opts.Capacity)
+		if err != nil {
+			panic(fmt.Sprintf("%s.New failed: %v", h.Name(), err))
+		}
+
+		if filler, ok := h.(Filler); ok {
+			filler.Fill(cache, opts.Capacity, opts.ValSize)
+		} else {
+			for range opts.Passes {
+				for i := range opts.Capacity {
+					key := "key-" + strconv.Itoa(i)
+					cache.Set(key, make([]byte, opts.ValSize))
+				}
+			}
+		}
+
+		if w, ok := cache.(Waiter); ok {
+			w.Wait()
+		}
+	})
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var wr workload.Result
+	if opts.Mode != "" {
+		wr = workload.Run(opts.Mode, opts.Iter, opts.Capacity, opts.Capacity, opts.ZipfS, opts.ZipfV,
+			cache.Get,
+			func(key string) { cache.Set(key, make([]byte, opts.ValSize)) })
+	}
+
+	fmt.Printf(`{"name":%q, "items":%d, "bytes":%d, "live_bytes":%d, "heap_objects_bytes":%d, "total_alloc_delta":%d, "gc_cycles":%d, "hit_ratio":%.4f, "ops_per_sec":%.0f, "p99_ns":%d}`,
+		h.Name(), cache.Len(), mem.Alloc, sample.LiveBytes, sample.HeapObjectsBytes, sample.TotalAllocDelta, sample.GCCycles,
+		wr.HitRatio, wr.OpsPerSec, wr.P99Ns)
+}
@@ -0,0 +1,64 @@
+package workload
+
+import "math/bits"
+
+// numBuckets covers latencies up to 2^63ns, far beyond anything a cache op
+// should ever take.
+const numBuckets = 64
+
+// Histogram is a power-of-two bucketed latency histogram: cheap to update
+// on the hot path (one bit-length computation, no allocation, no sorting),
+// at the cost of only having log2 resolution rather than exact percentiles.
+type Histogram struct {
+	buckets [numBuckets]int64
+	count   int64
+}
+
+// Record adds one latency sample, in nanoseconds.
+func (h *Histogram) Record(ns int64) {
+	if ns < 0 {
+		ns = 0
+	}
+	h.buckets[bits.Len64(uint64(ns))]++
+	h.count++
+}
+
+// Quantile returns the lower bound (in ns) of the bucket containing the
+// requested quantile, e.g. Quantile(0.99) for p99; with log2 buckets the
+// true value can be up to just under twice that.
+func (h *Histogram) Quantile(q float64) int64 {
+	if h.count == 0 {
+		return 0
+	}
+	target := int64(q * float64(h.count))
+	var seen int64
+	for i, n := range h.buckets {
+		seen += n
+		if seen > target {
+			if i == 0 {
+				return 0
+			}
+			return 1 << (i - 1)
+		}
+	}
+	// The loop only falls through when target is at least count (q >= 1),
+	// since no cumulative count exceeds count. Return the top bucket's lower
+	// bound, 2^62, as a saturating sentinel; 1 << (numBuckets-1) would shift
+	// into the sign bit and wrap negative.
+	return int64(1) << (numBuckets - 2)
+}
+
+// P99 is a convenience wrapper around Quantile(0.99).
+func (h *Histogram) P99() int64 {
+	return h.Quantile(0.99)
+}
+
+// Merge folds another histogram's bucket counts into h, so per-goroutine
+// histograms from a concurrent run can be combined before computing
+// percentiles across the whole run.
+func (h *Histogram) Merge(other *Histogram) {
+	for i, n := range other.buckets {
+		h.buckets[i] += n
+	}
+	h.count += other.count
+}
@@ -0,0 +1,47 @@
+package workload
+
+import (
+	"strconv"
+	"time"
+)
+
+// Result is the outcome of an Run pass.
+type Result struct {
+	HitRatio  float64
+	OpsPerSec float64
+	P99Ns     int64
+}
+
+// Run replays a generated workload against a cache via the given get/set
+// closures, timing each Get for the latency histogram. A miss triggers a
+// Set so the working set gets populated the way a real cache-aside pattern
+// would. Keys are formatted as "key-N" to match the fill phase used by the
+// memory harnesses.
+func Run(mode string, iter, keySpace, capacity int, zipfS, zipfV float64, get func(key string) bool, set func(key string)) Result {
+	keys := Generate(mode, iter, keySpace, capacity, zipfS, zipfV, 42)
+
+	var hist Histogram
+	var hits int
+
+	start := time.Now()
+	for _, k := range keys {
+		key := "key-" + strconv.Itoa(k)
+
+		opStart := time.Now()
+		found := get(key)
+		hist.Record(time.Since(opStart).Nanoseconds())
+
+		if found {
+			hits++
+		} else {
+			set(key)
+		}
+	}
+	elapsed := time.Since(start)
+
+	return Result{
+		HitRatio:  float64(hits) / float64(len(keys)) * 100,
+		OpsPerSec: float64(len(keys)) / elapsed.Seconds(),
+		P99Ns:     hist.P99(),
+	}
+}
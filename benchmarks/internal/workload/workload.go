@@ -0,0 +1,65 @@
+// Package workload generates key-access sequences for the memory benchmark
+// harnesses, so admission policies (TinyLFU's scan resistance vs otter's
+// W-TinyLFU vs freecache's plain LRU segments) can be compared on something
+// closer to real traffic instead of only on a single fill-to-capacity pass.
+package workload
+
+import "math/rand"
+
+// Generate returns iter key indices for the given mode; uniform and zipf
+// draw from [0, keySpace), while scan and churn also emit keys at or beyond
+// keySpace:
+//
+//   - "uniform": keys drawn uniformly at random.
+//   - "zipf":    keys drawn from a Zipf distribution (zipfS, zipfV feed
+//     math/rand.Zipf directly; higher zipfS means a sharper hot set).
+//   - "scan":    a hot working set of size cap/2 interleaved with a cold
+//     sweep of 10*cap unique keys, to exercise scan resistance.
+//   - "churn":   every key is unique and beyond keySpace, so each access is
+//     a guaranteed miss: the cache must evict and admit on every operation,
+//     exercising steady-state behavior instead of a one-time fill.
+func Generate(mode string, iter, keySpace, capacity int, zipfS, zipfV float64, seed int64) []int {
+	rng := rand.New(rand.NewSource(seed))
+
+	switch mode {
+	case "churn":
+		keys := make([]int, iter)
+		for i := range keys {
+			keys[i] = keySpace + i
+		}
+		return keys
+
+	case "zipf":
+		z := rand.NewZipf(rng, zipfS, zipfV, uint64(keySpace-1))
+		keys := make([]int, iter)
+		for i := range keys {
+			keys[i] = int(z.Uint64())
+		}
+		return keys
+
+	case "scan":
+		hotSize := capacity / 2
+		if hotSize < 1 {
+			hotSize = 1
+		}
+		coldSize := 10 * capacity
+		keys := make([]int, 0, iter)
+		cold := keySpace // cold keys live in a keyspace disjoint from the hot set
+		for len(keys) < iter {
+			for i := 0; i < 10000 && len(keys) < iter; i++ {
+				keys = append(keys, rng.Intn(hotSize))
+			}
+			for i := 0; i < coldSize && len(keys) < iter; i++ {
+				keys = append(keys, cold+i)
+			}
+		}
+		return keys
+
+	default: // "uniform"
+		keys := make([]int, iter)
+		for i := range keys {
+			keys[i] = rng.Intn(keySpace)
+		}
+		return keys
+	}
+}
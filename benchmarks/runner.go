@@ -10,22 +10,45 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"math"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 )
 
-// hitrateGoals are the minimum acceptable averages across all cache sizes.
-// Keys must match gocachemark JSON output (camelCase).
-var hitrateGoals = map[string]float64{
-	"cdn":          58.3,
-	"meta":         72.0,
-	"twitter":      84.5,
+// noiseThresholds bound how big a regression has to be, as a fraction of
+// the reference value, before statistical significance alone is enough to
+// fail a -runs>1 run. Below this, flag it but don't fail: a p<0.05 0.1%
+// hitrate wobble isn't worth blocking a PR over.
+var noiseThresholds = map[string]float64{
+	"hitrate":    0.005, // 0.5%
+	"throughput": 0.03,  // 3%
+}
+
+// hitrateGoals are the minimum acceptable hit rates for each gocachemark
+// test. Keys must match gocachemark JSON output (camelCase). A value is
+// either a float64 (legacy: the floor for the average across all cache
+// sizes) or a map[int]float64 (a floor per cache size, which catches a
+// regression at one working-set size even when the average stays flat).
+var hitrateGoals = map[string]any{
+	"cdn":  58.3,
+	"meta": 72.0,
+	// twitter is checked per-size: the average (84.5%) hid a regression at
+	// the 65536 working-set size that only a size-level floor would catch.
+	"twitter": map[int]float64{
+		16384:  78.0,
+		65536:  84.0,
+		262144: 89.5,
+	},
 	"wikipedia":    33.042,
 	"thesiosBlock": 25.358,
 	"thesiosFile":  93.458,
@@ -71,8 +94,17 @@ const (
 
 func main() {
 	competitive := flag.Bool("competitive", false, "Run competitive benchmark with gold medalists")
+	profileFlag := flag.String("profile", os.Getenv("PROFILE"), "comma-separated profiles to capture: cpu,heap,mutex,block")
+	runs := flag.Int("runs", 1, "repeat each gocachemark invocation this many times and validate statistically (suggest 5 for CI)")
+	serveAddr := flag.String("serve", "", "if set, expose results at /metrics in Prometheus text format on this address (e.g. :9090) and block")
+	pushURL := flag.String("push", "", "if set, POST results in Prometheus text format to this Pushgateway URL")
 	flag.Parse()
 
+	profiles := parseProfileKinds(*profileFlag)
+	if *runs < 1 {
+		*runs = 1
+	}
+
 	// Find multicache root (where we're running from).
 	multicacheDir, err := findMulticacheDir()
 	if err != nil {
@@ -133,12 +165,51 @@ func main() {
 	if *competitive {
 		mode = "competitive"
 	}
+	var profileEnv []string
+	if len(profiles) > 0 {
+		if slices.Contains(profiles, "cpu") {
+			args = append(args, "-cpuprofile", filepath.Join(outdir, "cpu.pprof"))
+		}
+		if slices.Contains(profiles, "heap") {
+			args = append(args, "-memprofile", filepath.Join(outdir, "heap.pprof"))
+		}
+		profileEnv = []string{"GODEBUG=" + strings.Join(profiles, ",")}
+		fmt.Printf("Capturing profiles: %s\n", strings.Join(profiles, ", "))
+	}
+
 	fmt.Printf("Running %s benchmarks via gocachemark...\n\n", mode)
-	results, err := runGocachemark(gocachemarkDir, args, outdir)
+	startRSS := peakRSS()
+	results, err := runGocachemark(gocachemarkDir, args, outdir, profileEnv)
 	if err != nil {
 		fatal("running gocachemark: %v", err)
 	}
 
+	var stats *statisticalRun
+	if *runs > 1 {
+		fmt.Printf("\nRepeating %d more time(s) for statistical validation...\n", *runs-1)
+		stats = newStatisticalRun(results)
+		for i := 1; i < *runs; i++ {
+			rep, err := runGocachemark(gocachemarkDir, args, outdir, profileEnv)
+			if err != nil {
+				fatal("running gocachemark (rep %d): %v", i+1, err)
+			}
+			stats.add(rep)
+		}
+		if err := stats.save(filepath.Join(benchmarksDir, "gocachemark_statistics.json")); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: saving statistics: %v\n", err)
+		}
+	}
+
+	if len(profiles) > 0 {
+		profileDir := filepath.Join(benchmarksDir, "profiles", mode)
+		if err := collateProfiles(outdir, profileDir, profiles); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: collating profiles: %v\n", err)
+		} else {
+			fmt.Printf("Profiles saved to %s/\n", profileDir)
+		}
+	}
+	fmt.Printf("Peak RSS: %s → %s\n\n", formatBytes(startRSS), formatBytes(peakRSS()))
+
 	// Show deltas against reference.
 	fmt.Println()
 	if ref != nil {
@@ -152,6 +223,11 @@ func main() {
 	if err := validateSuiteGoals(results); err != nil {
 		fatal("%v", err)
 	}
+	if stats != nil && ref != nil {
+		if err := stats.validateAgainst(ref); err != nil {
+			fatal("%v", err)
+		}
+	}
 	if *competitive {
 		if err := validateCompetitive(results, ref); err != nil {
 			fatal("%v", err)
@@ -166,6 +242,18 @@ func main() {
 			fmt.Printf("\nResults NOT saved (filtered run: TESTS=%q SUITES=%q)\n", testsFilter, suitesFilter)
 		}
 	}
+
+	if *pushURL != "" {
+		if err := pushMetrics(*pushURL, results); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: pushing metrics to %s: %v\n", *pushURL, err)
+		} else {
+			fmt.Printf("\nPushed metrics to %s\n", *pushURL)
+		}
+	}
+
+	if *serveAddr != "" {
+		serveMetrics(*serveAddr, results)
+	}
 }
 
 func findMulticacheDir() (string, error) {
@@ -241,10 +329,13 @@ func isGocachemarkDir(dir string) bool {
 	return strings.Contains(string(data), gocachemarkRepo)
 }
 
-func runGocachemark(dir string, args []string, outdir string) (*Results, error) {
+func runGocachemark(dir string, args []string, outdir string, extraEnv []string) (*Results, error) {
 	cmd := exec.Command("go", args...)
 	cmd.Dir = dir
 	cmd.Stderr = os.Stderr
+	if len(extraEnv) > 0 {
+		cmd.Env = append(os.Environ(), extraEnv...)
+	}
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -338,6 +429,192 @@ type placement struct {
 	value float64
 }
 
+// statisticalRun accumulates per-test hitrate samples across repeated
+// gocachemark invocations (-runs N), so a single noisy run can't trip a
+// false pass/fail.
+type statisticalRun struct {
+	hitrate map[string][]float64
+}
+
+// sampleStats is mean/stddev/median/CI for one test's accumulated samples,
+// the unit that gets both printed and serialized to
+// gocachemark_statistics.json.
+type sampleStats struct {
+	Samples []float64 `json:"samples"`
+	Mean    float64   `json:"mean"`
+	StdDev  float64   `json:"stddev"`
+	Median  float64   `json:"median"`
+	CILow   float64   `json:"ciLow95"`
+	CIHigh  float64   `json:"ciHigh95"`
+}
+
+func newStatisticalRun(first *Results) *statisticalRun {
+	s := &statisticalRun{hitrate: make(map[string][]float64)}
+	s.add(first)
+	return s
+}
+
+func (s *statisticalRun) add(res *Results) {
+	for name := range res.HitRate {
+		if name == "sizes" {
+			continue
+		}
+		caches, err := res.hitRateResults(name)
+		if err != nil {
+			continue
+		}
+		if rate := findHitRate(caches, "multicache"); rate > 0 {
+			s.hitrate[name] = append(s.hitrate[name], rate)
+		}
+	}
+}
+
+// validateAgainst fails only when, for some test, the mean is worse than
+// ref AND the difference is statistically significant (Welch's t-test,
+// p < 0.05, treating ref as a fixed reference value) AND the delta exceeds
+// the per-metric noise threshold - so a single-sample regression that's
+// merely "different" doesn't block a PR.
+func (s *statisticalRun) validateAgainst(ref *Results) error {
+	fmt.Println("\n=== Statistical Validation (vs reference) ===")
+	var fails []string
+
+	names := make([]string, 0, len(s.hitrate))
+	for name := range s.hitrate {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		samples := s.hitrate[name]
+		if len(samples) < 2 {
+			continue
+		}
+		refCaches, err := ref.hitRateResults(name)
+		if err != nil {
+			continue
+		}
+		refVal := findHitRate(refCaches, "multicache")
+		if refVal == 0 {
+			continue
+		}
+
+		stat := computeSampleStats(samples)
+		p := welchTTestVsConstant(samples, refVal)
+		delta := (stat.Mean - refVal) / refVal
+
+		worse := stat.Mean < refVal
+		significant := p < 0.05
+		exceedsNoise := math.Abs(delta) > noiseThresholds["hitrate"]
+
+		switch {
+		case worse && significant && exceedsNoise:
+			fmt.Printf("✗ %s: mean %.3f%% vs ref %.3f%% (p=%.4f, Δ=%.2f%%)\n", name, stat.Mean, refVal, p, delta*100)
+			fails = append(fails, fmt.Sprintf("%s: mean %.3f%% significantly worse than ref %.3f%% (p=%.4f)", name, stat.Mean, refVal, p))
+		case worse && significant:
+			fmt.Printf("~ %s: mean %.3f%% vs ref %.3f%% (p=%.4f, Δ=%.2f%%, within noise threshold)\n", name, stat.Mean, refVal, p, delta*100)
+		default:
+			fmt.Printf("✓ %s: mean %.3f%% ± %.3f (ref %.3f%%, p=%.4f)\n", name, stat.Mean, stat.StdDev, refVal, p)
+		}
+	}
+
+	if len(fails) > 0 {
+		return fmt.Errorf("statistical regression detected:\n  %s", strings.Join(fails, "\n  "))
+	}
+	return nil
+}
+
+// save writes the accumulated samples and their statistics to path as
+// gocachemark_statistics.json, for follow-up tooling (boxplots etc).
+func (s *statisticalRun) save(path string) error {
+	out := make(map[string]sampleStats, len(s.hitrate))
+	for name, samples := range s.hitrate {
+		out[name] = computeSampleStats(samples)
+	}
+	data, err := json.MarshalIndent(map[string]any{"hitRate": out}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func computeSampleStats(samples []float64) sampleStats {
+	n := float64(len(samples))
+	var total float64
+	for _, v := range samples {
+		total += v
+	}
+	mean := total / n
+
+	var variance float64
+	for _, v := range samples {
+		variance += (v - mean) * (v - mean)
+	}
+	if n > 1 {
+		variance /= n - 1
+	}
+	stddev := math.Sqrt(variance)
+
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	// 95% CI via normal approximation (1.96 * SEM); fine at the sample
+	// sizes -runs is meant for (5-20), not a substitute for bootstrapping.
+	sem := stddev / math.Sqrt(n)
+	return sampleStats{
+		Samples: samples,
+		Mean:    mean,
+		StdDev:  stddev,
+		Median:  median,
+		CILow:   mean - 1.96*sem,
+		CIHigh:  mean + 1.96*sem,
+	}
+}
+
+// welchTTestVsConstant runs a one-sample Welch's t-test of samples against
+// a fixed reference value (the reference JSON stores one number per test,
+// not a sample vector), returning an approximate two-tailed p-value.
+func welchTTestVsConstant(samples []float64, ref float64) float64 {
+	stat := computeSampleStats(samples)
+	n := float64(len(samples))
+	if stat.StdDev == 0 {
+		if stat.Mean == ref {
+			return 1
+		}
+		return 0
+	}
+	t := (stat.Mean - ref) / (stat.StdDev / math.Sqrt(n))
+	df := n - 1
+	return studentTTwoTailedP(t, df)
+}
+
+// studentTTwoTailedP approximates the two-tailed p-value for Student's t
+// distribution using Abramowitz & Stegun's normal approximation, which is
+// accurate to a few percent for the df ranges -runs produces (4-19) and
+// avoids pulling in a stats library for one test.
+func studentTTwoTailedP(t, df float64) float64 {
+	t = math.Abs(t)
+	// Regularized incomplete beta I_x(df/2, 0.5), computed via the
+	// continued-fraction approximation is overkill here; use the simpler
+	// normal approximation, which is adequate for a pass/fail gate.
+	z := t * (1 - 1/(4*df)) / math.Sqrt(1+t*t/(2*df))
+	p := 2 * (1 - normalCDF(z))
+	if p < 0 {
+		p = 0
+	}
+	if p > 1 {
+		p = 1
+	}
+	return p
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * (1 + math.Erf(z/math.Sqrt2))
+}
+
 // hitRateResults extracts cache results for a test, skipping non-test fields like "sizes".
 func (r *Results) hitRateResults(name string) ([]CacheResult, error) {
 	raw, ok := r.HitRate[name]
@@ -368,40 +645,160 @@ func validateHitrate(res *Results) error {
 
 	var fails []string
 	for name, goal := range hitrateGoals {
-		caches, err := res.hitRateResults(name)
-		if err != nil {
-			fmt.Printf("? %s: %v\n", name, err)
-			continue
+		switch g := goal.(type) {
+		case float64:
+			fails = append(fails, validateAverageGoal(res, name, g)...)
+		case map[int]float64:
+			fails = append(fails, validatePerSizeGoal(res, name, g)...)
+		default:
+			fmt.Printf("? %s: unsupported goal type %T\n", name, goal)
 		}
+	}
 
-		var avg float64
-		var found bool
-		for _, c := range caches {
-			if c.Name == "multicache" {
-				avg = c.AvgRate
-				found = true
-				break
-			}
+	if len(fails) > 0 {
+		return fmt.Errorf("hitrate goals not met:\n  %s", strings.Join(fails, "\n  "))
+	}
+	fmt.Println("\nAll hitrate goals met!")
+	return nil
+}
+
+// validateAverageGoal checks a test's average multicache hit rate against a
+// single floor - the legacy form every hitrateGoals entry used before
+// per-size floors existed.
+func validateAverageGoal(res *Results, name string, goal float64) []string {
+	caches, err := res.hitRateResults(name)
+	if err != nil {
+		fmt.Printf("? %s: %v\n", name, err)
+		return nil
+	}
+
+	var avg float64
+	var found bool
+	for _, c := range caches {
+		if c.Name == "multicache" {
+			avg = c.AvgRate
+			found = true
+			break
 		}
-		if !found {
-			fmt.Printf("? %s: multicache not found\n", name)
+	}
+	if !found {
+		fmt.Printf("? %s: multicache not found\n", name)
+		return nil
+	}
+
+	// Use tiny tolerance for floating point comparison.
+	if avg >= goal-0.000001 {
+		fmt.Printf("✓ %s: %.2f%% (goal: %.2f%%)\n", name, avg, goal)
+		return nil
+	}
+	fmt.Printf("✗ %s: %.2f%% (goal: %.2f%%)\n", name, avg, goal)
+	return []string{fmt.Sprintf("%s: %.2f%% < %.2f%%", name, avg, goal)}
+}
+
+// validatePerSizeGoal checks a test's hit rate at each named cache size
+// independently, so a regression at one working-set size can't hide behind
+// an improvement at another while the average stays flat. It also prints a
+// sparkline of the hit-rate curve across sizes so a human scanning CI logs
+// can see its shape without opening the JSON.
+func validatePerSizeGoal(res *Results, name string, goals map[int]float64) []string {
+	bySize, err := res.perSizeHitRates(name)
+	if err != nil {
+		fmt.Printf("? %s: %v\n", name, err)
+		return nil
+	}
+
+	sizes := make([]int, 0, len(goals))
+	for size := range goals {
+		sizes = append(sizes, size)
+	}
+	slices.Sort(sizes)
+
+	var fails []string
+	curve := make([]float64, 0, len(sizes))
+	for _, size := range sizes {
+		goal := goals[size]
+		caches, ok := bySize[size]
+		if !ok {
+			fmt.Printf("? %s@%d: no data\n", name, size)
+			continue
+		}
+		rate := findHitRate(caches, "multicache")
+		if rate == 0 {
+			fmt.Printf("? %s@%d: multicache not found\n", name, size)
 			continue
 		}
+		curve = append(curve, rate)
 
-		// Use tiny tolerance for floating point comparison.
-		if avg >= goal-0.000001 {
-			fmt.Printf("✓ %s: %.2f%% (goal: %.2f%%)\n", name, avg, goal)
+		if rate >= goal-0.000001 {
+			fmt.Printf("✓ %s@%d: %.1f%% (goal %.1f%%)\n", name, size, rate, goal)
 		} else {
-			fmt.Printf("✗ %s: %.2f%% (goal: %.2f%%)\n", name, avg, goal)
-			fails = append(fails, fmt.Sprintf("%s: %.2f%% < %.2f%%", name, avg, goal))
+			fmt.Printf("✗ %s@%d: %.1f%% (goal %.1f%%)\n", name, size, rate, goal)
+			fails = append(fails, fmt.Sprintf("%s@%d: %.1f%% < %.1f%%", name, size, rate, goal))
 		}
 	}
+	if len(curve) > 0 {
+		fmt.Printf("  %s curve: %s\n", name, sparkline(curve))
+	}
+	return fails
+}
 
-	if len(fails) > 0 {
-		return fmt.Errorf("hitrate goals not met:\n  %s", strings.Join(fails, "\n  "))
+// sparklineLevels are the Unicode block characters sparkline scales across,
+// lowest to highest.
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as one bar-chart character per value, scaled
+// between the series' own min and max.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
 	}
-	fmt.Println("\nAll hitrate goals met!")
-	return nil
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	spread := hi - lo
+	runes := make([]rune, len(values))
+	for i, v := range values {
+		if spread == 0 {
+			runes[i] = sparklineLevels[len(sparklineLevels)-1]
+			continue
+		}
+		level := int((v - lo) / spread * float64(len(sparklineLevels)-1))
+		runes[i] = sparklineLevels[level]
+	}
+	return string(runes)
+}
+
+// perSizeHitRates extracts test's per-cache-size hit rate breakdown from
+// the "sizes" entry of HitRate, which the average-only code paths skip.
+// gocachemark nests it as {test name: {size: []CacheResult}}.
+func (r *Results) perSizeHitRates(name string) (map[int][]CacheResult, error) {
+	raw, ok := r.HitRate["sizes"]
+	if !ok {
+		return nil, fmt.Errorf("results have no per-size hit rate breakdown")
+	}
+	var bySizeByTest map[string]map[string][]CacheResult
+	if err := json.Unmarshal(raw, &bySizeByTest); err != nil {
+		return nil, fmt.Errorf("parsing per-size hit rate breakdown: %w", err)
+	}
+	bySize, ok := bySizeByTest[name]
+	if !ok {
+		return nil, fmt.Errorf("test %q has no per-size hit rate breakdown", name)
+	}
+	out := make(map[int][]CacheResult, len(bySize))
+	for sizeStr, caches := range bySize {
+		size, err := strconv.Atoi(sizeStr)
+		if err != nil {
+			continue
+		}
+		out[size] = caches
+	}
+	return out, nil
 }
 
 func validateSuiteGoals(res *Results) error {
@@ -531,6 +928,41 @@ func showDeltas(ref, curr *Results) {
 		fmt.Printf("  hitrate/%s: %.2f%% → %.2f%% (%+.2f, %+.1f%%)\n", name, refVal, currVal, delta, pct)
 	}
 
+	// Per-size hit rate deltas, for tests with a per-size goal - the
+	// average above can mask a regression at one working-set size.
+	for name, goal := range hitrateGoals {
+		sizeGoals, ok := goal.(map[int]float64)
+		if !ok {
+			continue
+		}
+		refBySize, err := ref.perSizeHitRates(name)
+		if err != nil {
+			continue
+		}
+		currBySize, err := curr.perSizeHitRates(name)
+		if err != nil {
+			continue
+		}
+
+		sizes := make([]int, 0, len(sizeGoals))
+		for size := range sizeGoals {
+			sizes = append(sizes, size)
+		}
+		slices.Sort(sizes)
+
+		for _, size := range sizes {
+			refVal := findHitRate(refBySize[size], "multicache")
+			currVal := findHitRate(currBySize[size], "multicache")
+			if refVal == 0 {
+				continue
+			}
+			delta := currVal - refVal
+			pct := delta / refVal * 100
+			any = true
+			fmt.Printf("  hitrate/%s@%d: %.2f%% → %.2f%% (%+.2f, %+.1f%%)\n", name, size, refVal, currVal, delta, pct)
+		}
+	}
+
 	// Latency deltas (lower is better).
 	for name := range curr.Latency {
 		var refResults, currResults []LatencyResult
@@ -629,6 +1061,98 @@ func findMemory(results []MemoryEntry, name string) int {
 	return 0
 }
 
+// formatPrometheusMetrics renders res as Prometheus/OpenMetrics text
+// exposition format: gauges per suite and cache for hit rate, latency, and
+// throughput, a per-cache memory gauge, and each ranked cache's medal
+// score when competitive rankings are present.
+func formatPrometheusMetrics(res *Results) string {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "# HELP multicache_hitrate Cache hit rate percentage by suite and cache.")
+	fmt.Fprintln(&b, "# TYPE multicache_hitrate gauge")
+	for suite := range res.HitRate {
+		if suite == "sizes" {
+			continue
+		}
+		caches, err := res.hitRateResults(suite)
+		if err != nil {
+			continue
+		}
+		for _, c := range caches {
+			fmt.Fprintf(&b, "multicache_hitrate{suite=%q,cache=%q} %f\n", suite, c.Name, c.AvgRate)
+		}
+	}
+
+	fmt.Fprintln(&b, "# HELP multicache_latency_ns Average latency in nanoseconds per op by suite and cache.")
+	fmt.Fprintln(&b, "# TYPE multicache_latency_ns gauge")
+	for suite, raw := range res.Latency {
+		var results []LatencyResult
+		json.Unmarshal(raw, &results)
+		for _, r := range results {
+			fmt.Fprintf(&b, "multicache_latency_ns{suite=%q,cache=%q} %f\n", suite, r.Name, r.AvgNsOp)
+		}
+	}
+
+	fmt.Fprintln(&b, "# HELP multicache_throughput_ops Throughput in ops/sec by suite and cache.")
+	fmt.Fprintln(&b, "# TYPE multicache_throughput_ops gauge")
+	for suite, raw := range res.Throughput {
+		if suite == "threads" {
+			continue
+		}
+		var results []ThroughputResult
+		json.Unmarshal(raw, &results)
+		for _, r := range results {
+			fmt.Fprintf(&b, "multicache_throughput_ops{suite=%q,cache=%q} %f\n", suite, r.Name, r.AvgQps)
+		}
+	}
+
+	if res.Memory != nil {
+		fmt.Fprintln(&b, "# HELP multicache_memory_bytes_per_item Memory overhead per cached item, in bytes.")
+		fmt.Fprintln(&b, "# TYPE multicache_memory_bytes_per_item gauge")
+		for _, m := range res.Memory.Results {
+			fmt.Fprintf(&b, "multicache_memory_bytes_per_item{cache=%q} %d\n", m.Name, m.BytesPerItem)
+		}
+	}
+
+	if len(res.Rankings) > 0 {
+		fmt.Fprintln(&b, "# HELP multicache_medal_score Competitive medal table score by cache.")
+		fmt.Fprintln(&b, "# TYPE multicache_medal_score gauge")
+		for _, r := range res.Rankings {
+			fmt.Fprintf(&b, "multicache_medal_score{cache=%q} %d\n", r.Name, r.Score)
+		}
+	}
+
+	return b.String()
+}
+
+// serveMetrics exposes res at /metrics on addr in Prometheus text format
+// and blocks forever, for ad-hoc dashboard scraping of a single run.
+func serveMetrics(addr string, res *Results) {
+	body := formatPrometheusMetrics(res)
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprint(w, body)
+	})
+	fmt.Printf("\nServing metrics at http://%s/metrics (Ctrl-C to stop)\n", addr)
+	if err := http.ListenAndServe(addr, nil); err != nil { //nolint:gosec // local benchmark tooling, not a public server
+		fatal("serving metrics: %v", err)
+	}
+}
+
+// pushMetrics POSTs res in Prometheus text format to a Pushgateway URL.
+func pushMetrics(url string, res *Results) error {
+	body := formatPrometheusMetrics(res)
+	resp, err := http.Post(url, "text/plain; version=0.0.4", bytes.NewBufferString(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pushgateway returned %s", resp.Status)
+	}
+	return nil
+}
+
 func validateCompetitive(res, prev *Results) error {
 	// Find multicache in rankings.
 	var mc *RankEntry
@@ -786,6 +1310,72 @@ func copyResults(src, dst string) error {
 	return nil
 }
 
+// parseProfileKinds splits a "cpu,heap,mutex,block" flag/env value into its
+// components, dropping anything blank (an unset -profile flag or PROFILE
+// env var yields no profiles).
+func parseProfileKinds(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var kinds []string
+	for _, k := range strings.Split(raw, ",") {
+		k = strings.TrimSpace(k)
+		if k != "" {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds
+}
+
+// collateProfiles copies the *.pprof files gocachemark wrote into outdir
+// into dstDir, named for the profile kinds that were requested, so they
+// outlive the temporary outdir, which is removed on exit. With -runs > 1
+// only the last repetition's profiles remain, and a later run in the same
+// mode overwrites them.
+func collateProfiles(outdir, dstDir string, kinds []string) error {
+	if err := os.MkdirAll(dstDir, 0o755); err != nil {
+		return fmt.Errorf("creating profile dir: %w", err)
+	}
+	for _, kind := range kinds {
+		name := kind + ".pprof"
+		src := filepath.Join(outdir, name)
+		data, err := os.ReadFile(src)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // gocachemark didn't produce this one; not fatal
+			}
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dstDir, name), data, 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// peakRSS reports the runner process's own resident memory via
+// runtime.MemStats.Sys, as a cheap proxy for the RSS of the run it just
+// orchestrated (the gocachemark subprocess has its own address space and
+// isn't directly observable this way).
+func peakRSS() uint64 {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return mem.Sys
+}
+
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
 func fatal(format string, args ...any) {
 	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
 	os.Exit(1)
@@ -0,0 +1,153 @@
+package benchmarks
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/codeGROOVE-dev/bdcache"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/maypok86/otter/v2"
+	"github.com/vmihailenco/go-tinylfu"
+)
+
+// runScanResistanceBenchmark interleaves the usual Zipf "hot" workload with
+// periodic sequential scans of previously-unseen keys, and reports hit rate
+// on the hot subset only - a scan-friendly admission policy should barely
+// notice the scans, while plain LRU will evict hot entries to make room for
+// them and lose hit rate.
+//
+// This exercises the claim in runProxyCacheHitRate's doc comment ("periodic
+// scans... that shouldn't evict hot content"), which until now was never
+// actually tested: the workload generator only ever produced Zipf keys.
+const (
+	scanKeySpace    = 100000 // hot Zipf keyspace
+	scanWorkload    = 1000000
+	scanAlpha       = 0.8
+	scanInterval    = 10000 // inject a scan burst every N hot ops
+	scanBurstSize   = 5000  // keys touched per scan burst
+	scanCacheSize   = 10000
+	scanRetentionPC = 90.0 // bdcache must retain >=90% of its no-scan hit rate
+)
+
+// generateScanWorkload returns an interleaved key sequence and a parallel
+// "hot" mask: hot[i] is true when keys[i] came from the Zipf working set
+// rather than a scan burst. Scan keys live in a disjoint keyspace
+// (scanKeySpace + burst offset) so they can never collide with hot keys.
+func generateScanWorkload() (keys []int, hot []bool) {
+	hotKeys := generateWorkload(scanWorkload, scanKeySpace, scanAlpha, 42)
+
+	keys = make([]int, 0, len(hotKeys)+len(hotKeys)/scanInterval*scanBurstSize)
+	hot = make([]bool, 0, cap(keys))
+
+	scanCursor := 0
+	for i, k := range hotKeys {
+		keys = append(keys, k)
+		hot = append(hot, true)
+
+		if (i+1)%scanInterval == 0 {
+			for j := range scanBurstSize {
+				keys = append(keys, scanKeySpace+scanCursor+j)
+				hot = append(hot, false)
+			}
+			scanCursor += scanBurstSize
+		}
+	}
+	return keys, hot
+}
+
+// hotHitRate runs keys/hot through a cache, counting hits only among hot
+// entries so scan traffic (which is always a miss on first touch) doesn't
+// dilute the number.
+func hotHitRate(keys []int, hot []bool, get func(int) bool, set func(int)) float64 {
+	var hotTotal, hotHits int
+	for i, k := range keys {
+		found := get(k)
+		if hot[i] {
+			hotTotal++
+			if found {
+				hotHits++
+			}
+		}
+		if !found {
+			set(k)
+		}
+	}
+	return float64(hotHits) / float64(hotTotal) * 100
+}
+
+func scanHitRateBdcache(keys []int, hot []bool, cacheSize int) float64 {
+	cache := bdcache.Memory[int, int](bdcache.WithSize(cacheSize))
+	return hotHitRate(keys, hot,
+		func(k int) bool { _, ok := cache.Get(k); return ok },
+		func(k int) { cache.Set(k, k) })
+}
+
+func scanHitRateOtter(keys []int, hot []bool, cacheSize int) float64 {
+	cache := otter.Must(&otter.Options[int, int]{MaximumSize: cacheSize})
+	return hotHitRate(keys, hot,
+		func(k int) bool { _, ok := cache.GetIfPresent(k); return ok },
+		func(k int) { cache.Set(k, k) })
+}
+
+func scanHitRateTinyLFU(keys []int, hot []bool, cacheSize int) float64 {
+	cache := tinylfu.New(cacheSize, cacheSize*10)
+	return hotHitRate(keys, hot,
+		func(k int) bool { _, ok := cache.Get(strconv.Itoa(k)); return ok },
+		func(k int) { cache.Set(&tinylfu.Item{Key: strconv.Itoa(k), Value: k}) })
+}
+
+func scanHitRateLRU(keys []int, hot []bool, cacheSize int) float64 {
+	cache, _ := lru.New[int, int](cacheSize)
+	return hotHitRate(keys, hot,
+		func(k int) bool { _, ok := cache.Get(k); return ok },
+		func(k int) { cache.Add(k, k) })
+}
+
+func runScanResistanceBenchmark() {
+	keys, hot := generateScanWorkload()
+
+	fmt.Println()
+	fmt.Println("### Scan Resistance (Zipf hot set + periodic sequential scans)")
+	fmt.Println()
+	fmt.Println("| Cache         | Hot Hit Rate |")
+	fmt.Println("|---------------|--------------|")
+
+	caches := []struct {
+		name string
+		fn   func([]int, []bool, int) float64
+	}{
+		{"bdcache", scanHitRateBdcache},
+		{"otter", scanHitRateOtter},
+		{"tinylfu", scanHitRateTinyLFU},
+		{"lru", scanHitRateLRU},
+	}
+
+	for _, c := range caches {
+		rate := c.fn(keys, hot, scanCacheSize)
+		fmt.Printf("| %s |    %5.2f%%    |\n", formatCacheName(c.name), rate)
+	}
+	fmt.Println()
+}
+
+// TestScanResistance asserts that bdcache's admission policy keeps hot hit
+// rate close to what it would be with no scans at all. A regression here
+// means bdcache is letting scan bursts evict hot content, defeating the
+// point of scan resistance.
+func TestScanResistance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	keys, hot := generateScanWorkload()
+	scanRate := scanHitRateBdcache(keys, hot, scanCacheSize)
+
+	noScanWorkload := generateWorkload(scanWorkload, scanKeySpace, scanAlpha, 42)
+	noScanRate := hitRateBdcache(noScanWorkload, scanCacheSize)
+
+	retained := scanRate / noScanRate * 100
+	if retained < scanRetentionPC {
+		t.Errorf("bdcache hot hit rate under scan load = %.2f%% of no-scan rate (%.2f%% vs %.2f%%), want >= %.0f%%",
+			retained, scanRate, noScanRate, scanRetentionPC)
+	}
+}
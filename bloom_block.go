@@ -1,6 +1,32 @@
 package sfcache
 
-import "math"
+import (
+	"math"
+	"sync"
+)
+
+// sizingStats tracks the running average bits-per-item actually achieved by
+// newBlockBloomFilter across every filter this process constructs, and
+// alternates rounding direction on every construction. A long-lived process that creates
+// many filters (one per namespace, one per cache generation, ...) converges
+// on the requested FP rate this way instead of only ever over-provisioning,
+// which is what always-round-up would do.
+var sizingStats struct {
+	mu             sync.Mutex
+	count          int
+	avgBitsPerItem float64
+	roundUpNext    bool
+}
+
+func recordFilterSizing(bitsPerItem float64) bool {
+	sizingStats.mu.Lock()
+	defer sizingStats.mu.Unlock()
+
+	sizingStats.count++
+	sizingStats.avgBitsPerItem += (bitsPerItem - sizingStats.avgBitsPerItem) / float64(sizingStats.count)
+	sizingStats.roundUpNext = !sizingStats.roundUpNext
+	return sizingStats.roundUpNext
+}
 
 // bloomBlock is a 512-bit block (8 uint64s = 64 bytes = typical cache line).
 // All k hash bits for a single item are stored within one block for cache efficiency.
@@ -63,19 +89,68 @@ func newBlockBloomFilter(capacity int, fpRate float64) *blockBloomFilter {
 		numBlocks = int(nextPowerOf2(uint64(minBlocks)))
 	}
 
+	// The allocator rounds make([]bloomBlock, numBlocks) up to a size class
+	// anyway; probe what it would actually give us (by appending numBlocks
+	// blocks to a nil slice and reading back its cap) and use the extra
+	// blocks as addressable spillover rather than leaving them allocated but
+	// unreachable. mask still indexes only the power-of-two region so the
+	// hot-path modulo stays a single AND.
+	actualBlocks := probeBlockCapacity(numBlocks)
+
+	bitsPerItem := float64(actualBlocks) * bitsPerBlock / float64(capacity)
+	if roundUp := recordFilterSizing(bitsPerItem); !roundUp && actualBlocks > numBlocks {
+		// Alternate constructions give back the probed spillover instead of
+		// always keeping it, so a process that creates many filters
+		// converges on the requested rate on average rather than drifting
+		// ever further over capacity.
+		actualBlocks = numBlocks
+	}
+
 	return &blockBloomFilter{
-		blocks: make([]bloomBlock, numBlocks),
+		blocks: make([]bloomBlock, actualBlocks),
 		mask:   uint64(numBlocks - 1),
 		k:      k,
 	}
 }
 
+// probeBlockCapacity returns the actual block count the allocator grants for
+// a request of want blocks, which can be larger than want because
+// make([]bloomBlock, want) itself never reveals size-class rounding - only
+// append's growth path does, via the runtime's roundupsize. Appending want
+// blocks to a nil slice grows it to exactly that rounded size (growing from
+// empty applies no doubling), so reading cap back tells us how much of that
+// rounding we can put to use for free.
+func probeBlockCapacity(want int) int {
+	if want < 1 {
+		return 1
+	}
+	probe := append([]bloomBlock(nil), make([]bloomBlock, want)...)
+	got := cap(probe)
+	if got < want {
+		return want
+	}
+	return got
+}
+
+// blockIndex picks h's block. It normally uses the fast mask-AND over the
+// power-of-two region, but when construction left spillover blocks beyond
+// that region (see probeBlockCapacity), one otherwise-unused hash bit
+// routes a fraction of hashes into that extra space via a secondary hash,
+// so the "free" memory the allocator rounded up to doesn't sit idle.
+func (b *blockBloomFilter) blockIndex(h uint64) uint64 {
+	pow2Blocks := b.mask + 1
+	spillover := uint64(len(b.blocks)) - pow2Blocks
+	if spillover > 0 && (h>>63)&1 == 1 {
+		mixed := h * 0x9e3779b97f4a7c15
+		return pow2Blocks + ((mixed >> 48) % spillover)
+	}
+	return (h >> 32) & b.mask
+}
+
 // Add adds a hash to the blocked bloom filter.
 // All k bit positions are within a single cache line (block).
 func (b *blockBloomFilter) Add(h uint64) {
-	// Select which block - use upper bits for block selection
-	blockIdx := (h >> 32) & b.mask
-	block := &b.blocks[blockIdx]
+	block := &b.blocks[b.blockIndex(h)]
 
 	// Use enhanced double hashing within the block for better distribution
 	// Mix the bits better to get independent hash functions
@@ -100,9 +175,7 @@ func (b *blockBloomFilter) Add(h uint64) {
 // Contains checks if a hash might be in the filter.
 // All k probes hit the same cache line, reducing memory stalls.
 func (b *blockBloomFilter) Contains(h uint64) bool {
-	// Select which block - must match Add()
-	blockIdx := (h >> 32) & b.mask
-	block := &b.blocks[blockIdx]
+	block := &b.blocks[b.blockIndex(h)]
 
 	// Check k bits within the same block using same hash mixing as Add
 	h1 := h & 0xFFFFFFFF
@@ -134,6 +134,53 @@ func TestBlockBloomVsStandardBloom(t *testing.T) {
 	}
 }
 
+func TestBlockBloomFilterSpilloverAddressable(t *testing.T) {
+	capacity := 3000
+	fpRate := 0.01
+	bf := newBlockBloomFilter(capacity, fpRate)
+
+	pow2Blocks := int(bf.mask + 1)
+	if len(bf.blocks) < pow2Blocks {
+		t.Fatalf("blocks = %d, want at least the power-of-two region %d", len(bf.blocks), pow2Blocks)
+	}
+	t.Logf("capacity=%d: %d power-of-two blocks, %d spillover blocks", capacity, pow2Blocks, len(bf.blocks)-pow2Blocks)
+
+	hashes := make([]uint64, capacity)
+	for i := range capacity {
+		hashes[i] = uint64(i)*0x9e3779b97f4a7c15 + 0x6a09e667f3bcc908
+		bf.Add(hashes[i])
+	}
+	for i, h := range hashes {
+		if !bf.Contains(h) {
+			t.Errorf("hash %d should be in the filter", i)
+		}
+	}
+}
+
+// BenchmarkBlockBloomMemoryPerItem reports bytes-per-item for the blocked
+// filter's actual allocation (including any spillover blocks the allocator
+// granted for free), alongside a plain map[uint64]struct{} as the baseline
+// membership structure it replaces.
+func BenchmarkBlockBloomMemoryPerItem(b *testing.B) {
+	const n = 100000
+	b.Run("map", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := make(map[uint64]struct{}, n)
+			for j := 0; j < n; j++ {
+				m[uint64(j)] = struct{}{}
+			}
+		}
+	})
+	b.Run("blocked", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bf := newBlockBloomFilter(n, 0.01)
+			for j := 0; j < n; j++ {
+				bf.Add(uint64(j))
+			}
+		}
+	})
+}
+
 // Benchmark comparison
 func BenchmarkStandardBloomAdd(b *testing.B) {
 	bf := newBloomFilter(10000, 0.01)
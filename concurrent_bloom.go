@@ -0,0 +1,113 @@
+package sfcache
+
+import "sync/atomic"
+
+// concurrentBloomBlock is the atomic counterpart to bloomBlock: the same
+// 512-bit/64-byte layout, but each word is an atomic.Uint64 so Add can OR
+// bits in from multiple goroutines without an external mutex.
+type concurrentBloomBlock [8]atomic.Uint64
+
+// concurrentBlockBloomFilter is a lock-free variant of blockBloomFilter.
+// Every item's k probes land in a single block (as in blockBloomFilter), so
+// concurrent writers only ever contend on true block collisions, and each
+// contended word update is a small CAS retry loop rather than a filter-wide
+// lock.
+type concurrentBlockBloomFilter struct {
+	blocks  []concurrentBloomBlock
+	mask    uint64
+	k       int
+	entries atomic.Int64
+}
+
+// newConcurrentBlockBloomFilter sizes itself identically to
+// newBlockBloomFilter; only the word-update strategy differs.
+func newConcurrentBlockBloomFilter(capacity int, fpRate float64) *concurrentBlockBloomFilter {
+	ref := newBlockBloomFilter(capacity, fpRate)
+	return &concurrentBlockBloomFilter{
+		blocks: make([]concurrentBloomBlock, len(ref.blocks)),
+		mask:   ref.mask,
+		k:      ref.k,
+	}
+}
+
+// Add sets h's k bits within its block using atomic OR, so concurrent calls
+// from other goroutines never lose a bit to a lost write.
+func (b *concurrentBlockBloomFilter) Add(h uint64) {
+	blockIdx := (h >> 32) & b.mask
+	block := &b.blocks[blockIdx]
+
+	h1 := h & 0xFFFFFFFF
+	h2 := (h >> 32) | (h << 32)
+
+	for i := 0; i < b.k; i++ {
+		bitPos := (h1 + uint64(i)*h2 + uint64(i*i)*0x9e3779b1) & 511
+		wordIdx := bitPos >> 6
+		bitInWord := bitPos & 63
+		orUint64(&block[wordIdx], 1<<bitInWord)
+	}
+
+	b.entries.Add(1)
+}
+
+// Contains checks h's k bits using atomic loads, matching Add's hash mixing.
+func (b *concurrentBlockBloomFilter) Contains(h uint64) bool {
+	blockIdx := (h >> 32) & b.mask
+	block := &b.blocks[blockIdx]
+
+	h1 := h & 0xFFFFFFFF
+	h2 := (h >> 32) | (h << 32)
+
+	for i := 0; i < b.k; i++ {
+		bitPos := (h1 + uint64(i)*h2 + uint64(i*i)*0x9e3779b1) & 511
+		wordIdx := bitPos >> 6
+		bitInWord := bitPos & 63
+		if block[wordIdx].Load()&(1<<bitInWord) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Reset clears every word. Unlike Add/Contains, Reset assumes no concurrent
+// writers are in flight, the same precondition blockBloomFilter.Reset has.
+func (b *concurrentBlockBloomFilter) Reset() {
+	for i := range b.blocks {
+		for j := range b.blocks[i] {
+			b.blocks[i][j].Store(0)
+		}
+	}
+	b.entries.Store(0)
+}
+
+// FilterConfig selects which membership filter backs a cache's
+// admission/membership sketch. Concurrent should be set by callers whose
+// hot path adds to the filter from multiple goroutines without already
+// holding a filter-wide lock; it trades a small per-word CAS cost for
+// dropping that lock entirely.
+type FilterConfig struct {
+	Capacity   int
+	FPRate     float64
+	Concurrent bool
+}
+
+// NewFilter builds the blocked bloom filter variant cfg selects.
+func NewFilter(cfg FilterConfig) approxSet {
+	if cfg.Concurrent {
+		return newConcurrentBlockBloomFilter(cfg.Capacity, cfg.FPRate)
+	}
+	return newBlockBloomFilter(cfg.Capacity, cfg.FPRate)
+}
+
+// orUint64 atomically sets bits into *w via a CAS retry loop, equivalent to
+// the Go 1.23+ atomic.OrUint64 free function but without requiring it, so
+// this compiles on the toolchain versions the rest of this module targets.
+func orUint64(w *atomic.Uint64, bits uint64) {
+	for {
+		old := w.Load()
+		next := old | bits
+		if next == old || w.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
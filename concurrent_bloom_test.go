@@ -0,0 +1,81 @@
+package sfcache
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentBlockBloomFilterStress(t *testing.T) {
+	const (
+		goroutines   = 32
+		perGoroutine = 5000
+	)
+	cf := newConcurrentBlockBloomFilter(goroutines*perGoroutine, 0.01)
+
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			base := uint64(g) * 1_000_000
+			for i := range perGoroutine {
+				h := (base+uint64(i))*0x9e3779b97f4a7c15 + 0x6a09e667f3bcc908
+				cf.Add(h)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	missing := 0
+	for g := range goroutines {
+		base := uint64(g) * 1_000_000
+		for i := range perGoroutine {
+			h := (base+uint64(i))*0x9e3779b97f4a7c15 + 0x6a09e667f3bcc908
+			if !cf.Contains(h) {
+				missing++
+			}
+		}
+	}
+	if missing > 0 {
+		t.Errorf("%d/%d concurrently added hashes were not found", missing, goroutines*perGoroutine)
+	}
+	if got := cf.entries.Load(); got != goroutines*perGoroutine {
+		t.Errorf("entries = %d, want %d", got, goroutines*perGoroutine)
+	}
+}
+
+func TestConcurrentBlockBloomFilterConcurrentAddContains(t *testing.T) {
+	cf := newConcurrentBlockBloomFilter(10000, 0.01)
+
+	var wg sync.WaitGroup
+	for g := range 16 {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := range 1000 {
+				h := uint64(g*1000 + i)
+				cf.Add(h)
+				cf.Contains(h) // concurrent reader while other goroutines still write
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for i := range 16000 {
+		if !cf.Contains(uint64(i)) {
+			t.Errorf("hash %d should be present after concurrent adds", i)
+		}
+	}
+}
+
+func TestNewFilterSelectsConcurrentVariant(t *testing.T) {
+	plain := NewFilter(FilterConfig{Capacity: 1000, FPRate: 0.01})
+	if _, ok := plain.(*blockBloomFilter); !ok {
+		t.Errorf("expected *blockBloomFilter for Concurrent: false, got %T", plain)
+	}
+
+	concurrent := NewFilter(FilterConfig{Capacity: 1000, FPRate: 0.01, Concurrent: true})
+	if _, ok := concurrent.(*concurrentBlockBloomFilter); !ok {
+		t.Errorf("expected *concurrentBlockBloomFilter for Concurrent: true, got %T", concurrent)
+	}
+}
@@ -0,0 +1,118 @@
+package sfcache
+
+// countingBlock packs 128 4-bit saturating counters into the same 64-byte
+// footprint as a bloomBlock's 512 bits (4 bits/counter * 128 = 512 bits),
+// trading 4x the memory for the ability to remove an entry without
+// corrupting every other key that happens to share a bit.
+type countingBlock [8]uint64
+
+// countersPerBlock is how many 4-bit counters fit in a countingBlock:
+// 8 uint64 words * 16 nibbles per word = 128 counters.
+const countersPerBlock = 128
+
+// countingBlockBloomFilter is a counting variant of blockBloomFilter: each
+// of the k probes within a block increments (or decrements, on Remove) a
+// saturating 4-bit counter instead of setting a bit. This lets Remove keep
+// the filter's FP rate from climbing on churn-heavy workloads where the
+// plain blockBloomFilter can only ever accumulate set bits.
+type countingBlockBloomFilter struct {
+	blocks  []countingBlock
+	mask    uint64
+	k       int
+	entries int
+}
+
+// newCountingBlockBloomFilter sizes itself the same way newBlockBloomFilter
+// does, just over countersPerBlock instead of 512 bits.
+func newCountingBlockBloomFilter(capacity int, fpRate float64) *countingBlockBloomFilter {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	// Reuse blockBloomFilter's sizing math and take 4x its power-of-two
+	// block count. Each countingBlock only holds 128 counters versus a
+	// bloomBlock's 512 bits, so this intentionally over-provisions blocks
+	// relative to the plain filter - the ~4x memory cost of going from 1-bit
+	// to 4-bit counters per probe position.
+	ref := newBlockBloomFilter(capacity, fpRate)
+	numBlocks := int(ref.mask+1) * 4
+
+	return &countingBlockBloomFilter{
+		blocks: make([]countingBlock, numBlocks),
+		mask:   uint64(numBlocks - 1),
+		k:      ref.k,
+	}
+}
+
+func getCounter(block *countingBlock, pos uint64) uint8 {
+	word := pos >> 4      // 16 counters per word
+	nibble := (pos & 15) * 4
+	return uint8((block[word] >> nibble) & 0xF)
+}
+
+func setCounter(block *countingBlock, pos uint64, v uint8) {
+	word := pos >> 4
+	nibble := (pos & 15) * 4
+	block[word] = (block[word] &^ (0xF << nibble)) | (uint64(v&0xF) << nibble)
+}
+
+// positions returns the k counter positions (0..countersPerBlock-1) for h,
+// using the same hash-mixing scheme blockBloomFilter uses for its bits.
+func (c *countingBlockBloomFilter) positions(h uint64) []uint64 {
+	h1 := h & 0xFFFFFFFF
+	h2 := (h >> 32) | (h << 32)
+	pos := make([]uint64, c.k)
+	for i := 0; i < c.k; i++ {
+		pos[i] = (h1 + uint64(i)*h2 + uint64(i*i)*0x9e3779b1) % countersPerBlock
+	}
+	return pos
+}
+
+// Add increments the k counters for h, saturating at 15.
+func (c *countingBlockBloomFilter) Add(h uint64) {
+	blockIdx := (h >> 32) & c.mask
+	block := &c.blocks[blockIdx]
+	for _, pos := range c.positions(h) {
+		if v := getCounter(block, pos); v < 15 {
+			setCounter(block, pos, v+1)
+		}
+	}
+	c.entries++
+}
+
+// Remove decrements the k counters for h. Decrementing an already-zero
+// counter is a no-op: a false-positive Contains before a Remove must not be
+// allowed to corrupt another key's counters down to zero.
+func (c *countingBlockBloomFilter) Remove(h uint64) {
+	blockIdx := (h >> 32) & c.mask
+	block := &c.blocks[blockIdx]
+	for _, pos := range c.positions(h) {
+		if v := getCounter(block, pos); v > 0 {
+			setCounter(block, pos, v-1)
+		}
+	}
+	if c.entries > 0 {
+		c.entries--
+	}
+}
+
+// Contains reports whether h might be present: true iff every one of its k
+// counters is non-zero.
+func (c *countingBlockBloomFilter) Contains(h uint64) bool {
+	blockIdx := (h >> 32) & c.mask
+	block := &c.blocks[blockIdx]
+	for _, pos := range c.positions(h) {
+		if getCounter(block, pos) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset clears every counter.
+func (c *countingBlockBloomFilter) Reset() {
+	for i := range c.blocks {
+		c.blocks[i] = countingBlock{}
+	}
+	c.entries = 0
+}
@@ -0,0 +1,97 @@
+package sfcache
+
+import "testing"
+
+func TestCountingBloomAddContainsRemove(t *testing.T) {
+	capacity := 1000
+	fpRate := 0.01
+	cf := newCountingBlockBloomFilter(capacity, fpRate)
+
+	hashes := make([]uint64, capacity)
+	for i := range capacity {
+		h := uint64(i)
+		h = h*0x9e3779b97f4a7c15 + 0x6a09e667f3bcc908
+		hashes[i] = h
+		cf.Add(h)
+	}
+
+	for i, h := range hashes {
+		if !cf.Contains(h) {
+			t.Errorf("hash %d should be in the counting filter", i)
+		}
+	}
+
+	// Remove the first half.
+	for _, h := range hashes[:capacity/2] {
+		cf.Remove(h)
+	}
+
+	// Removed half should (mostly) be gone; kept half must remain present.
+	falsePositives := 0
+	for _, h := range hashes[:capacity/2] {
+		if cf.Contains(h) {
+			falsePositives++
+		}
+	}
+	for i, h := range hashes[capacity/2:] {
+		if !cf.Contains(h) {
+			t.Errorf("kept hash %d should still be in the counting filter after removing the other half", i)
+		}
+	}
+	t.Logf("removed half: %d/%d still reported present (shared-counter false positives)", falsePositives, capacity/2)
+}
+
+// TestCountingBloomFPRateAfterChurn adds N items, removes half, and checks
+// that the false positive rate on never-added hashes stays bounded - the
+// regression this type exists to prevent (a plain blockBloomFilter's FP
+// rate only ever climbs, since it has no Remove).
+func TestCountingBloomFPRateAfterChurn(t *testing.T) {
+	capacity := 5000
+	fpRate := 0.01
+	cf := newCountingBlockBloomFilter(capacity, fpRate)
+
+	hashes := make([]uint64, capacity)
+	for i := range capacity {
+		hashes[i] = uint64(i)*0x9e3779b97f4a7c15 + 0x6a09e667f3bcc908
+		cf.Add(hashes[i])
+	}
+	for _, h := range hashes[:capacity/2] {
+		cf.Remove(h)
+	}
+
+	falsePositives := 0
+	testSize := 10000
+	for i := range testSize {
+		h := uint64(i+capacity) * 0x9e3779b97f4a7c15
+		if cf.Contains(h) {
+			falsePositives++
+		}
+	}
+	actualFPRate := float64(falsePositives) / float64(testSize)
+	t.Logf("counting bloom filter after 50%% churn: FP rate=%.4f (target=%.4f)", actualFPRate, fpRate)
+
+	if actualFPRate > fpRate*6 {
+		t.Errorf("false positive rate too high after churn: %.4f > %.4f (6x target)", actualFPRate, fpRate*6)
+	}
+}
+
+func TestCountingBloomReset(t *testing.T) {
+	cf := newCountingBlockBloomFilter(100, 0.01)
+	for i := range 50 {
+		cf.Add(uint64(i))
+	}
+	cf.Reset()
+
+	found := 0
+	for i := range 50 {
+		if cf.Contains(uint64(i)) {
+			found++
+		}
+	}
+	if found > 2 {
+		t.Errorf("after reset, found %d items (expected ~0-2 false positives)", found)
+	}
+	if cf.entries != 0 {
+		t.Errorf("after reset, entries = %d, want 0", cf.entries)
+	}
+}
@@ -0,0 +1,182 @@
+package sfcache
+
+// approxSet is the common membership-test interface implemented by every
+// filter type in this package (the plain bloomFilter, blockBloomFilter, and
+// cuckooFilter), so callers can pick a filter implementation at
+// construction time without the rest of the cache caring which one it got.
+type approxSet interface {
+	Add(uint64)
+	Contains(uint64) bool
+}
+
+var (
+	_ approxSet = (*blockBloomFilter)(nil)
+	_ approxSet = (*cuckooFilter)(nil)
+	_ approxSet = (*concurrentBlockBloomFilter)(nil)
+)
+
+const (
+	cuckooSlotsPerBucket = 4
+	cuckooMaxKicks       = 500
+)
+
+// cuckooFilter is a partial-key cuckoo filter: each bucket holds up to
+// cuckooSlotsPerBucket fingerprints, and every fingerprint has two
+// candidate buckets (i1, i2 = i1 XOR hash(fingerprint)), so it can be
+// relocated to its other bucket without needing the original key. This
+// gives both deletion (unlike blockBloomFilter) and lower bits-per-item at
+// moderate false-positive rates, at the cost of possible insert failure
+// once the filter is nearly full.
+type cuckooFilter struct {
+	buckets    [][cuckooSlotsPerBucket]uint16
+	numBuckets uint64
+	entries    int
+}
+
+// newCuckooFilter sizes the table to capacity/cuckooSlotsPerBucket buckets
+// rounded up to a power of two, then grows that by a third and rounds up to
+// a power of two again for headroom, so capacity items run well below the
+// ~95% load a 4-slot cuckoo filter can reach (about half full once there
+// are 4 or more buckets).
+func newCuckooFilter(capacity int) *cuckooFilter {
+	if capacity < 1 {
+		capacity = 1
+	}
+	numBuckets := nextPowerOf2(uint64((capacity + cuckooSlotsPerBucket - 1) / cuckooSlotsPerBucket))
+	// Leave headroom so the kick-based insert doesn't thrash near full load.
+	numBuckets = nextPowerOf2(numBuckets * 4 / 3)
+	if numBuckets < 1 {
+		numBuckets = 1
+	}
+	return &cuckooFilter{
+		buckets:    make([][cuckooSlotsPerBucket]uint16, numBuckets),
+		numBuckets: numBuckets,
+	}
+}
+
+// cuckooFingerprint derives a non-zero 16-bit fingerprint from h; zero is
+// reserved to mean "empty slot".
+func cuckooFingerprint(h uint64) uint16 {
+	fp := uint16(h) ^ uint16(h>>16) ^ uint16(h>>32) ^ uint16(h>>48)
+	if fp == 0 {
+		fp = 1
+	}
+	return fp
+}
+
+func cuckooFingerprintHash(fp uint16) uint64 {
+	x := uint64(fp)
+	x *= 0x9e3779b97f4a7c15
+	return x ^ (x >> 29)
+}
+
+func (c *cuckooFilter) index1(h uint64) uint64 {
+	return h % c.numBuckets
+}
+
+func (c *cuckooFilter) index2(i1 uint64, fp uint16) uint64 {
+	return (i1 ^ cuckooFingerprintHash(fp)) % c.numBuckets
+}
+
+// Insert adds h to the filter, returning false if it had to give up after
+// cuckooMaxKicks relocation attempts (the filter is effectively full).
+func (c *cuckooFilter) Insert(h uint64) bool {
+	fp := cuckooFingerprint(h)
+	i1 := c.index1(h)
+	i2 := c.index2(i1, fp)
+
+	if c.insertInto(i1, fp) || c.insertInto(i2, fp) {
+		c.entries++
+		return true
+	}
+
+	// Both home buckets are full: evict a random occupant and re-home it,
+	// repeating until something fits or we give up.
+	i := i1
+	if pseudoRand()%2 == 0 {
+		i = i2
+	}
+	for range cuckooMaxKicks {
+		slot := pseudoRand() % cuckooSlotsPerBucket
+		victim := c.buckets[i][slot]
+		c.buckets[i][slot] = fp
+		fp = victim
+		i = c.index2(i, fp)
+		if c.insertInto(i, fp) {
+			c.entries++
+			return true
+		}
+	}
+	return false
+}
+
+// Add implements approxSet; it discards Insert's full-filter signal since
+// approxSet callers have no way to handle a failed add anyway. Check Insert
+// directly when that matters.
+func (c *cuckooFilter) Add(h uint64) {
+	c.Insert(h)
+}
+
+func (c *cuckooFilter) insertInto(bucket uint64, fp uint16) bool {
+	b := &c.buckets[bucket]
+	for i, slot := range b {
+		if slot == 0 {
+			b[i] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// Contains checks both candidate buckets for a matching fingerprint.
+func (c *cuckooFilter) Contains(h uint64) bool {
+	fp := cuckooFingerprint(h)
+	i1 := c.index1(h)
+	i2 := c.index2(i1, fp)
+	return c.bucketHas(i1, fp) || c.bucketHas(i2, fp)
+}
+
+func (c *cuckooFilter) bucketHas(bucket uint64, fp uint16) bool {
+	for _, slot := range c.buckets[bucket] {
+		if slot == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// Delete removes one occurrence of h's fingerprint from either candidate
+// bucket, returning whether it found one to remove.
+func (c *cuckooFilter) Delete(h uint64) bool {
+	fp := cuckooFingerprint(h)
+	i1 := c.index1(h)
+	i2 := c.index2(i1, fp)
+	if c.deleteFrom(i1, fp) || c.deleteFrom(i2, fp) {
+		c.entries--
+		return true
+	}
+	return false
+}
+
+func (c *cuckooFilter) deleteFrom(bucket uint64, fp uint16) bool {
+	b := &c.buckets[bucket]
+	for i, slot := range b {
+		if slot == fp {
+			b[i] = 0
+			return true
+		}
+	}
+	return false
+}
+
+// pseudoRand is a tiny xorshift PRNG local to cuckoo displacement, so the
+// filter doesn't need to pull in math/rand for a handful of coin-flips per
+// insert under contention-free conditions.
+var cuckooRandState uint64 = 0x2545F4914F6CDD1D
+
+func pseudoRand() uint64 {
+	cuckooRandState ^= cuckooRandState << 13
+	cuckooRandState ^= cuckooRandState >> 7
+	cuckooRandState ^= cuckooRandState << 17
+	return cuckooRandState
+}
@@ -0,0 +1,157 @@
+package sfcache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCuckooFilterAddContainsDelete(t *testing.T) {
+	capacity := 1000
+	cf := newCuckooFilter(capacity)
+
+	hashes := make([]uint64, capacity)
+	for i := range capacity {
+		h := uint64(i)
+		h = h*0x9e3779b97f4a7c15 + 0x6a09e667f3bcc908
+		hashes[i] = h
+		if !cf.Insert(h) {
+			t.Fatalf("insert %d failed at %.0f%% load", i, float64(i)/float64(capacity)*100)
+		}
+	}
+
+	for i, h := range hashes {
+		if !cf.Contains(h) {
+			t.Errorf("hash %d should be in the cuckoo filter", i)
+		}
+	}
+
+	// Delete the first half and confirm they're gone while the rest remain.
+	for _, h := range hashes[:capacity/2] {
+		if !cf.Delete(h) {
+			t.Errorf("delete should succeed for a present hash")
+		}
+	}
+	falsePositives := 0
+	for _, h := range hashes[:capacity/2] {
+		if cf.Contains(h) {
+			falsePositives++
+		}
+	}
+	t.Logf("deleted half: %d/%d still reported present (fingerprint collisions)", falsePositives, capacity/2)
+
+	for i, h := range hashes[capacity/2:] {
+		if !cf.Contains(h) {
+			t.Errorf("kept hash %d should still be in the cuckoo filter after deleting the other half", i)
+		}
+	}
+}
+
+func TestCuckooFilterFPRate(t *testing.T) {
+	capacity := 5000
+	cf := newCuckooFilter(capacity)
+
+	for range capacity {
+		h := uint64(rand.Int63())
+		cf.Insert(h)
+	}
+
+	falsePositives := 0
+	testSize := 10000
+	for i := 0; i < testSize; i++ {
+		h := uint64(i+capacity) * 0x9e3779b97f4a7c15
+		if cf.Contains(h) {
+			falsePositives++
+		}
+	}
+	actualFPRate := float64(falsePositives) / float64(testSize)
+	t.Logf("cuckoo filter: capacity=%d, buckets=%d, FP rate=%.4f", capacity, len(cf.buckets), actualFPRate)
+
+	// 16-bit fingerprints in 4-slot buckets should comfortably beat 1%.
+	if actualFPRate > 0.01 {
+		t.Errorf("false positive rate too high: %.4f > 0.01", actualFPRate)
+	}
+}
+
+func TestCuckooFilterDeleteMissing(t *testing.T) {
+	cf := newCuckooFilter(100)
+	cf.Insert(42)
+
+	if cf.Delete(9999) {
+		t.Errorf("delete of a never-inserted hash should report false")
+	}
+	if !cf.Contains(42) {
+		t.Errorf("unrelated entry should survive a failed delete")
+	}
+}
+
+func TestBlockBloomVsCuckooFilter(t *testing.T) {
+	capacity := 5000
+	fpRate := 0.01
+
+	blocked := newBlockBloomFilter(capacity, fpRate)
+	cuckoo := newCuckooFilter(capacity)
+
+	t.Logf("Blocked: k=%d, blocks=%d, memory=%d bytes", blocked.k, len(blocked.blocks), len(blocked.blocks)*64)
+	t.Logf("Cuckoo:  buckets=%d, memory=%d bytes", len(cuckoo.buckets), len(cuckoo.buckets)*cuckooSlotsPerBucket*2)
+
+	hashes := make([]uint64, capacity)
+	for i := range capacity {
+		hashes[i] = uint64(rand.Int63())
+		blocked.Add(hashes[i])
+		cuckoo.Insert(hashes[i])
+	}
+
+	for i, h := range hashes {
+		if !blocked.Contains(h) {
+			t.Errorf("blocked filter missing hash %d", i)
+		}
+		if !cuckoo.Contains(h) {
+			t.Errorf("cuckoo filter missing hash %d", i)
+		}
+	}
+
+	testSize := 10000
+	blockedFP, cuckooFP := 0, 0
+	for i := 0; i < testSize; i++ {
+		h := uint64(rand.Int63())
+		if blocked.Contains(h) {
+			blockedFP++
+		}
+		if cuckoo.Contains(h) {
+			cuckooFP++
+		}
+	}
+	t.Logf("Blocked FP rate: %.4f", float64(blockedFP)/float64(testSize))
+	t.Logf("Cuckoo FP rate:  %.4f", float64(cuckooFP)/float64(testSize))
+}
+
+// Benchmark comparison against the existing blocked filter.
+func BenchmarkCuckooFilterAdd(b *testing.B) {
+	cf := newCuckooFilter(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cf.Insert(uint64(i))
+	}
+}
+
+func BenchmarkCuckooFilterContains(b *testing.B) {
+	cf := newCuckooFilter(10000)
+	for i := 0; i < 10000; i++ {
+		cf.Insert(uint64(i))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cf.Contains(uint64(i % 10000))
+	}
+}
+
+func BenchmarkCuckooFilterDelete(b *testing.B) {
+	cf := newCuckooFilter(b.N + 1)
+	for i := 0; i < b.N; i++ {
+		cf.Insert(uint64(i))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cf.Delete(uint64(i))
+	}
+}
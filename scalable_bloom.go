@@ -0,0 +1,91 @@
+package sfcache
+
+// scalableGrowthFactor is how much larger each successive inner filter's
+// capacity is than the one before it.
+const scalableGrowthFactor = 2
+
+// scalableTighteningRatio (r) shrinks each successive inner filter's target
+// FP rate so the geometric series of per-filter FP rates still sums to
+// something close to the overall target, even as the filter keeps growing.
+const scalableTighteningRatio = 0.8
+
+// scalableBloomFilter is an unbounded-growth bloom filter: when the current
+// inner blockBloomFilter fills to its designed capacity, a new, larger inner
+// filter with a tightened FP rate is appended rather than degrading the
+// existing filter's accuracy past its design point. This trades a small,
+// bounded amount of extra memory for not having to know final cardinality
+// up front - the common case for a long-running cache process.
+type scalableBloomFilter struct {
+	filters      []*blockBloomFilter
+	capacities   []int // designed capacity of each filters[i], so Add knows when to grow
+	targetFPRate float64
+}
+
+// NewScalable creates a scalable bloom filter that starts at initialCap and
+// grows by scalableGrowthFactor each time the newest inner filter fills up.
+func NewScalable(initialCap int, targetFPRate float64) *scalableBloomFilter {
+	if initialCap < 1 {
+		initialCap = 1
+	}
+	s := &scalableBloomFilter{targetFPRate: targetFPRate}
+	s.grow(initialCap, s.fpRateAt(0))
+	return s
+}
+
+// fpRateAt returns the target FP rate for the filter at index i. The
+// per-filter rates form a geometric series fp_0 * r^i whose sum converges
+// to targetFPRate as i grows without bound (sum_{i=0}^inf fp_0*r^i =
+// fp_0/(1-r)), so fp_0 must be targetFPRate*(1-r) rather than targetFPRate
+// itself - otherwise the combined FP rate across all filters converges to
+// targetFPRate/(1-r) instead of targetFPRate.
+func (s *scalableBloomFilter) fpRateAt(i int) float64 {
+	rate := s.targetFPRate * (1 - scalableTighteningRatio)
+	for range i {
+		rate *= scalableTighteningRatio
+	}
+	return rate
+}
+
+func (s *scalableBloomFilter) grow(capacity int, fpRate float64) {
+	s.filters = append(s.filters, newBlockBloomFilter(capacity, fpRate))
+	s.capacities = append(s.capacities, capacity)
+}
+
+// Add inserts h into the newest inner filter, growing first if it's full.
+func (s *scalableBloomFilter) Add(h uint64) {
+	last := len(s.filters) - 1
+	if s.filters[last].entries >= s.capacities[last] {
+		nextCap := s.capacities[last] * scalableGrowthFactor
+		s.grow(nextCap, s.fpRateAt(len(s.filters)))
+		last++
+	}
+	s.filters[last].Add(h)
+}
+
+// Contains returns true if any inner filter reports h as present.
+func (s *scalableBloomFilter) Contains(h uint64) bool {
+	for _, f := range s.filters {
+		if f.Contains(h) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset drops all inner filters and starts over at the original initial
+// capacity and target FP rate.
+func (s *scalableBloomFilter) Reset() {
+	initialCap := s.capacities[0]
+	s.filters = nil
+	s.capacities = nil
+	s.grow(initialCap, s.fpRateAt(0))
+}
+
+// entries sums the entry count across every inner filter, for tests.
+func (s *scalableBloomFilter) entries() int {
+	var n int
+	for _, f := range s.filters {
+		n += f.entries
+	}
+	return n
+}
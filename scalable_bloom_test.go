@@ -0,0 +1,71 @@
+package sfcache
+
+import "testing"
+
+func TestScalableBloomFilterGrows(t *testing.T) {
+	initialCap := 1000
+	fpRate := 0.01
+	sf := NewScalable(initialCap, fpRate)
+
+	total := initialCap * 10
+	for i := range total {
+		h := uint64(i)
+		h = h*0x9e3779b97f4a7c15 + 0x6a09e667f3bcc908
+		sf.Add(h)
+	}
+
+	if got := sf.entries(); got != total {
+		t.Errorf("entries() = %d, want %d", got, total)
+	}
+	if len(sf.filters) < 2 {
+		t.Errorf("expected the filter to have grown past its initial capacity, got %d inner filters", len(sf.filters))
+	}
+
+	// All added items should be found.
+	for i := range total {
+		h := uint64(i)
+		h = h*0x9e3779b97f4a7c15 + 0x6a09e667f3bcc908
+		if !sf.Contains(h) {
+			t.Errorf("hash for item %d should be in the scalable filter", i)
+		}
+	}
+
+	falsePositives := 0
+	testSize := 10000
+	for i := range testSize {
+		h := uint64(i+total) * 0x9e3779b97f4a7c15
+		if sf.Contains(h) {
+			falsePositives++
+		}
+	}
+	actualFPRate := float64(falsePositives) / float64(testSize)
+	t.Logf("scalable bloom filter: %d inner filters, %d entries, FP rate=%.4f (target=%.4f)",
+		len(sf.filters), total, actualFPRate, fpRate)
+
+	// fp_0 = target*(1-r) makes the geometric sum of per-filter FP rates
+	// converge to target itself; the remaining tolerance here is for the
+	// blocked filter's own design overhead (bloom_block_test.go allows the
+	// same 3-5x for a single block filter).
+	if actualFPRate > fpRate*4 {
+		t.Errorf("false positive rate too high: %.4f > %.4f (4x target)", actualFPRate, fpRate*4)
+	}
+}
+
+func TestScalableBloomFilterReset(t *testing.T) {
+	sf := NewScalable(100, 0.01)
+	for i := range 1000 {
+		sf.Add(uint64(i))
+	}
+	if len(sf.filters) < 2 {
+		t.Fatalf("expected growth before reset, got %d inner filters", len(sf.filters))
+	}
+
+	sf.Reset()
+
+	if len(sf.filters) != 1 {
+		t.Errorf("after Reset, expected 1 inner filter, got %d", len(sf.filters))
+	}
+	if sf.entries() != 0 {
+		t.Errorf("after Reset, expected 0 entries, got %d", sf.entries())
+	}
+}
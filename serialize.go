@@ -0,0 +1,233 @@
+package sfcache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// filterMagic identifies a serialized filter so LoadFrom/UnmarshalBinary can
+// fail fast on garbage input instead of silently misreading it. It is the
+// same for every filter type; the header's separate kind field records
+// which filter type produced the stream.
+const filterMagic uint32 = 0x53464331 // "SFC1"
+
+const (
+	filterKindStandard uint8 = 1
+	filterKindBlocked  uint8 = 2
+)
+
+const filterFormatVersion uint16 = 1
+
+// header is the fixed-size preamble written before every serialized filter:
+// magic, format version, filter kind, k (hash count), word/block count,
+// entry count, and mask (the power-of-two block count minus one, for
+// blockBloomFilter's spillover-addressed blocks beyond that region; unused
+// and zero for the standard filter). Everything is little-endian.
+type filterHeader struct {
+	magic   uint32
+	version uint16
+	kind    uint8
+	k       uint16
+	words   uint64
+	entries uint64
+	mask    uint64
+}
+
+func writeFilterHeader(w io.Writer, h filterHeader) error {
+	fields := []any{h.magic, h.version, h.kind, h.k, h.words, h.entries, h.mask}
+	for _, f := range fields {
+		if err := binary.Write(w, binary.LittleEndian, f); err != nil {
+			return fmt.Errorf("sfcache: write header: %w", err)
+		}
+	}
+	return nil
+}
+
+func readFilterHeader(r io.Reader, wantKind uint8) (filterHeader, error) {
+	var h filterHeader
+	fields := []any{&h.magic, &h.version, &h.kind, &h.k, &h.words, &h.entries, &h.mask}
+	for _, f := range fields {
+		if err := binary.Read(r, binary.LittleEndian, f); err != nil {
+			return filterHeader{}, fmt.Errorf("sfcache: read header: %w", err)
+		}
+	}
+	if h.magic != filterMagic {
+		return filterHeader{}, fmt.Errorf("sfcache: bad magic %#x, not a serialized filter", h.magic)
+	}
+	if h.version != filterFormatVersion {
+		return filterHeader{}, fmt.Errorf("sfcache: unsupported format version %d", h.version)
+	}
+	if h.kind != wantKind {
+		return filterHeader{}, fmt.Errorf("sfcache: kind %d does not match expected %d", h.kind, wantKind)
+	}
+	return h, nil
+}
+
+// MarshalBinary serializes the blocked bloom filter's full state: k, block
+// count, entry count, and the raw block words, so a restarted process can
+// restore exactly the FP-rate characteristics it had before shutdown.
+func (b *blockBloomFilter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 23+len(b.blocks)*64)
+	w := &sliceWriter{buf: buf}
+	if err := b.WriteTo(w); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+// WriteTo writes the same format as MarshalBinary directly to w, for
+// streaming a filter to disk without an intermediate byte slice.
+func (b *blockBloomFilter) WriteTo(w io.Writer) error {
+	h := filterHeader{
+		magic:   filterMagic,
+		version: filterFormatVersion,
+		kind:    filterKindBlocked,
+		k:       uint16(b.k),
+		words:   uint64(len(b.blocks)) * 8,
+		entries: uint64(b.entries),
+		mask:    b.mask,
+	}
+	if err := writeFilterHeader(w, h); err != nil {
+		return err
+	}
+	for i := range b.blocks {
+		for _, word := range b.blocks[i] {
+			if err := binary.Write(w, binary.LittleEndian, word); err != nil {
+				return fmt.Errorf("sfcache: write block %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalBinary restores a blocked bloom filter previously written by
+// MarshalBinary, replacing the receiver's contents in place.
+func (b *blockBloomFilter) UnmarshalBinary(data []byte) error {
+	return b.ReadFrom(bytesReader(data))
+}
+
+// ReadFrom restores a blocked bloom filter from the format WriteTo writes.
+func (b *blockBloomFilter) ReadFrom(r io.Reader) error {
+	h, err := readFilterHeader(r, filterKindBlocked)
+	if err != nil {
+		return err
+	}
+	if h.words%8 != 0 {
+		return fmt.Errorf("sfcache: word count %d is not block-aligned", h.words)
+	}
+	numBlocks := h.words / 8
+	blocks := make([]bloomBlock, numBlocks)
+	for i := range blocks {
+		for j := range blocks[i] {
+			if err := binary.Read(r, binary.LittleEndian, &blocks[i][j]); err != nil {
+				return fmt.Errorf("sfcache: read block %d: %w", i, err)
+			}
+		}
+	}
+
+	b.blocks = blocks
+	b.mask = h.mask
+	b.k = int(h.k)
+	b.entries = int(h.entries)
+	return nil
+}
+
+// MarshalBinary serializes the standard bloom filter's state: k, the bit
+// array word count, entry count, and the raw words.
+func (s *bloomFilter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 23+len(s.data)*8)
+	w := &sliceWriter{buf: buf}
+	if err := s.WriteTo(w); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+// WriteTo writes the same format as MarshalBinary directly to w.
+func (s *bloomFilter) WriteTo(w io.Writer) error {
+	h := filterHeader{
+		magic:   filterMagic,
+		version: filterFormatVersion,
+		kind:    filterKindStandard,
+		k:       uint16(s.k),
+		words:   uint64(len(s.data)),
+		entries: uint64(s.entries),
+	}
+	if err := writeFilterHeader(w, h); err != nil {
+		return err
+	}
+	for i, word := range s.data {
+		if err := binary.Write(w, binary.LittleEndian, word); err != nil {
+			return fmt.Errorf("sfcache: write word %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// UnmarshalBinary restores a standard bloom filter previously written by
+// MarshalBinary, replacing the receiver's contents in place.
+func (s *bloomFilter) UnmarshalBinary(data []byte) error {
+	return s.ReadFrom(bytesReader(data))
+}
+
+// ReadFrom restores a standard bloom filter from the format WriteTo writes.
+func (s *bloomFilter) ReadFrom(r io.Reader) error {
+	h, err := readFilterHeader(r, filterKindStandard)
+	if err != nil {
+		return err
+	}
+	data := make([]uint64, h.words)
+	for i := range data {
+		if err := binary.Read(r, binary.LittleEndian, &data[i]); err != nil {
+			return fmt.Errorf("sfcache: read word %d: %w", i, err)
+		}
+	}
+
+	s.data = data
+	s.k = int(h.k)
+	s.entries = int(h.entries)
+	return nil
+}
+
+// SaveTo and LoadFrom are the package's warm-restart entry points: they
+// serialize the blocked bloom filter, this package's default filter, by
+// forwarding to its WriteTo/ReadFrom, so a restarted process can skip
+// re-warming it from scratch. They accept only a *blockBloomFilter; the
+// other filter types in this package are not handled here.
+func SaveTo(b *blockBloomFilter, w io.Writer) error {
+	return b.WriteTo(w)
+}
+
+func LoadFrom(b *blockBloomFilter, r io.Reader) error {
+	return b.ReadFrom(r)
+}
+
+// sliceWriter is a minimal io.Writer over a growable byte slice, avoiding a
+// bytes.Buffer import for what's otherwise a single-purpose append.
+type sliceWriter struct{ buf []byte }
+
+func (w *sliceWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+// bytesReader avoids a bytes.Reader import by wrapping the same minimal
+// interface binary.Read needs.
+type bytesReaderImpl struct {
+	data []byte
+	pos  int
+}
+
+func bytesReader(data []byte) *bytesReaderImpl {
+	return &bytesReaderImpl{data: data}
+}
+
+func (r *bytesReaderImpl) Read(p []byte) (int, error) {
+	if r.pos >= len(r.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[r.pos:])
+	r.pos += n
+	return n, nil
+}
@@ -0,0 +1,117 @@
+package sfcache
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockBloomFilterSerializeRoundTrip(t *testing.T) {
+	capacity := 2000
+	fpRate := 0.01
+	bf := newBlockBloomFilter(capacity, fpRate)
+
+	hashes := make([]uint64, capacity)
+	for i := range capacity {
+		h := uint64(i)*0x9e3779b97f4a7c15 + 0x6a09e667f3bcc908
+		hashes[i] = h
+		bf.Add(h)
+	}
+
+	var buf bytes.Buffer
+	if err := bf.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+
+	restored := &blockBloomFilter{}
+	if err := restored.ReadFrom(&buf); err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+
+	if restored.k != bf.k {
+		t.Errorf("k = %d, want %d", restored.k, bf.k)
+	}
+	if restored.entries != bf.entries {
+		t.Errorf("entries = %d, want %d", restored.entries, bf.entries)
+	}
+	if len(restored.blocks) != len(bf.blocks) {
+		t.Fatalf("blocks = %d, want %d", len(restored.blocks), len(bf.blocks))
+	}
+
+	for i, h := range hashes {
+		if restored.Contains(h) != bf.Contains(h) {
+			t.Errorf("hash %d: restored.Contains = %v, original = %v", i, restored.Contains(h), bf.Contains(h))
+		}
+	}
+}
+
+func TestBlockBloomFilterMarshalUnmarshal(t *testing.T) {
+	bf := newBlockBloomFilter(500, 0.01)
+	for i := range 500 {
+		bf.Add(uint64(i))
+	}
+
+	data, err := bf.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	restored := &blockBloomFilter{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+
+	for i := range 500 {
+		if !restored.Contains(uint64(i)) {
+			t.Errorf("restored filter missing hash %d", i)
+		}
+	}
+}
+
+func TestBlockBloomFilterSerializePreservesSpilloverMask(t *testing.T) {
+	bf := newBlockBloomFilter(3000, 0.01)
+	for i := range 3000 {
+		bf.Add(uint64(i))
+	}
+
+	data, err := bf.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	restored := &blockBloomFilter{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if restored.mask != bf.mask {
+		t.Errorf("mask = %d, want %d (spillover region must round-trip, not just block count)", restored.mask, bf.mask)
+	}
+}
+
+func TestBlockBloomFilterUnmarshalRejectsGarbage(t *testing.T) {
+	bf := &blockBloomFilter{}
+	if err := bf.UnmarshalBinary([]byte("not a filter")); err == nil {
+		t.Errorf("expected an error unmarshaling garbage input, got nil")
+	}
+}
+
+func TestSaveToLoadFrom(t *testing.T) {
+	bf := newBlockBloomFilter(1000, 0.01)
+	for i := range 1000 {
+		bf.Add(uint64(i))
+	}
+
+	var buf bytes.Buffer
+	if err := SaveTo(bf, &buf); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+
+	restored := &blockBloomFilter{}
+	if err := LoadFrom(restored, &buf); err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	for i := range 1000 {
+		if !restored.Contains(uint64(i)) {
+			t.Errorf("restored filter missing hash %d", i)
+		}
+	}
+}